@@ -0,0 +1,36 @@
+// Command seed imports the JSON plant catalog into a SQLite database.
+//
+//	go run -tags sqlite ./cmd/seed -catalog catalog -db leaflove.db
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/example/leaf-love-go/internal/data"
+)
+
+func main() {
+	catalogPath := flag.String("catalog", "catalog", "catalog directory or file to import")
+	dbPath := flag.String("db", "leaflove.db", "SQLite database to create or update")
+	flag.Parse()
+
+	store, err := data.NewStore(*catalogPath)
+	if err != nil {
+		log.Fatalf("load catalog %s:\n%v", *catalogPath, err)
+	}
+	snap := store.Snapshot()
+
+	ctx := context.Background()
+	repo, err := data.OpenSQLite(ctx, *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer repo.Close()
+
+	if err := repo.Import(ctx, snap.Plants, snap.Version); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("imported %d plants into %s (version %s)", len(snap.Plants), *dbPath, snap.Version)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// maxPlantBody caps admin request bodies; a plant is a few hundred bytes.
+const maxPlantBody = 1 << 20
+
+// requireAdmin guards the plant write endpoints. Every call needs
+// "Authorization: Bearer <admin token>"; without a configured token the
+// admin API is disabled.
+func (s *server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := s.cfg.AdminToken
+		if token == "" {
+			writeError(w, http.StatusForbidden, "admin API disabled: ADMIN_TOKEN is not set")
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="leaflove-admin"`)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleCreatePlant serves POST /api/plants.
+func (s *server) handleCreatePlant(w http.ResponseWriter, r *http.Request) {
+	var p models.Plant
+	if !decodePlant(w, r, &p) || !validPlant(w, p) {
+		return
+	}
+	if err := s.repo.Create(r.Context(), p); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/plants/"+p.ID)
+	writeJSON(w, http.StatusCreated, p)
+}
+
+// handleReplacePlant serves PUT /api/plants/{id}: the body replaces the
+// whole plant. Retirement is kept unless the body sets retiredAt.
+func (s *server) handleReplacePlant(w http.ResponseWriter, r *http.Request) {
+	s.updatePlant(w, r, func(cur models.Plant) models.Plant {
+		return models.Plant{ID: cur.ID, RetiredAt: cur.RetiredAt}
+	})
+}
+
+// handlePatchPlant serves PATCH /api/plants/{id}: only the fields in the
+// body change.
+func (s *server) handlePatchPlant(w http.ResponseWriter, r *http.Request) {
+	s.updatePlant(w, r, func(cur models.Plant) models.Plant { return cur })
+}
+
+// updatePlant decodes the body on top of base(current plant), validates
+// the result and stores it.
+func (s *server) updatePlant(w http.ResponseWriter, r *http.Request, base func(models.Plant) models.Plant) {
+	id := r.PathValue("id")
+	cur, err := s.repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	p := base(cur)
+	if !decodePlant(w, r, &p) {
+		return
+	}
+	if p.ID != id {
+		writeError(w, http.StatusBadRequest, "id in body does not match URL")
+		return
+	}
+	if !validPlant(w, p) {
+		return
+	}
+	if err := s.repo.Update(r.Context(), p); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// handleRetirePlant serves DELETE /api/plants/{id} as a soft delete.
+func (s *server) handleRetirePlant(w http.ResponseWriter, r *http.Request) {
+	if err := s.repo.Retire(r.Context(), r.PathValue("id"), time.Now()); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodePlant decodes the request body on top of p, so a PATCH only
+// replaces the fields it names. Unknown fields and anything after the
+// object are rejected.
+func decodePlant(w http.ResponseWriter, r *http.Request, p *models.Plant) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPlantBody))
+	if err != nil {
+		writeRequestError(w, bodyError(err, "reading request body: "))
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid plant JSON: "+err.Error())
+		return false
+	}
+	if dec.More() {
+		writeError(w, http.StatusBadRequest, "invalid plant JSON: unexpected data after the object")
+		return false
+	}
+	return true
+}
+
+// validPlant answers an invalid plant with a problem listing every bad
+// field.
+func validPlant(w http.ResponseWriter, p models.Plant) bool {
+	var ve *models.ValidationError
+	if !errors.As(p.Validate(), &ve) {
+		return true
+	}
+	writeProblem(w, problem{
+		Type:   problemInvalidPlant,
+		Title:  "Invalid plant",
+		Status: http.StatusUnprocessableEntity,
+		Detail: ve.Error(),
+		Errors: ve.Errors,
+	})
+	return false
+}
+
+func writeRepoError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, data.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, data.ErrExists):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "storage error")
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+const testAdminToken = "s3cret"
+
+// newAdminServer is newTestServer with the admin API enabled.
+func newAdminServer(t *testing.T) http.Handler {
+	t.Helper()
+	s := newTestServer(t)
+	s.cfg.AdminToken = testAdminToken
+	return s.routes()
+}
+
+// adminDo sends an authorized admin request and returns the response.
+func adminDo(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, r)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// getPlant fetches a plant through the public API.
+func getPlant(t *testing.T, h http.Handler, id string) (models.Plant, int) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/plants/"+id, nil))
+	var env struct{ Data models.Plant }
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return env.Data, rec.Code
+}
+
+const newPlant = `{"id": "ivy", "name": "English Ivy", "image": "/static/ivy.jpg", "lightCondition": ["low-light"],
+	"careLevel": "low", "plantType": "foliage", "location": "indoor", "size": "small"}`
+
+func TestAdminCreate(t *testing.T) {
+	h := newAdminServer(t)
+	rec := adminDo(t, h, http.MethodPost, "/api/plants", newPlant)
+	if rec.Code != http.StatusCreated || rec.Header().Get("Location") != "/api/plants/ivy" {
+		t.Fatalf("POST = %d, Location %q: %s", rec.Code, rec.Header().Get("Location"), rec.Body)
+	}
+	if p, code := getPlant(t, h, "ivy"); code != http.StatusOK || p.Name != "English Ivy" {
+		t.Errorf("GET after POST = %d %+v", code, p)
+	}
+	if rec := adminDo(t, h, http.MethodPost, "/api/plants", newPlant); rec.Code != http.StatusConflict {
+		t.Errorf("POST of an existing id = %d, want 409", rec.Code)
+	}
+}
+
+func TestAdminReplaceAndPatch(t *testing.T) {
+	h := newAdminServer(t)
+
+	// PUT replaces the whole plant: fields left out are cleared.
+	put := strings.Replace(newPlant, `"ivy"`, `"pothos"`, 1)
+	if rec := adminDo(t, h, http.MethodPut, "/api/plants/pothos", put); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	p, _ := getPlant(t, h, "pothos")
+	if p.Name != "English Ivy" || len(p.Aliases) != 0 || p.Description != "" {
+		t.Errorf("after PUT = %+v, want only the body's fields", p)
+	}
+
+	// PATCH changes only the fields it names.
+	if rec := adminDo(t, h, http.MethodPatch, "/api/plants/snake-plant", `{"careLevel": "medium"}`); rec.Code != http.StatusOK {
+		t.Fatalf("PATCH = %d: %s", rec.Code, rec.Body)
+	}
+	p, _ = getPlant(t, h, "snake-plant")
+	if p.CareLevel != "medium" || p.Name != "Snake Plant" || len(p.Aliases) == 0 {
+		t.Errorf("after PATCH = %+v, want only careLevel changed", p)
+	}
+
+	tests := []struct {
+		name, method, path, body string
+		status                   int
+	}{
+		{"id mismatch", http.MethodPut, "/api/plants/pothos", newPlant, http.StatusBadRequest},
+		{"missing plant", http.MethodPatch, "/api/plants/nope", `{"careLevel": "low"}`, http.StatusNotFound},
+		{"invalid plant", http.MethodPatch, "/api/plants/pothos", `{"careLevel": "banana"}`, http.StatusUnprocessableEntity},
+		{"unknown field", http.MethodPatch, "/api/plants/pothos", `{"colour": "green"}`, http.StatusBadRequest},
+		{"trailing data", http.MethodPatch, "/api/plants/pothos", `{"careLevel": "low"} {"careLevel": "high"}`, http.StatusBadRequest},
+		{"too large", http.MethodPatch, "/api/plants/pothos", `{"description": "` + strings.Repeat("x", maxPlantBody) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := adminDo(t, h, tc.method, tc.path, tc.body)
+			if rec.Code != tc.status || rec.Header().Get("Content-Type") != "application/problem+json" {
+				t.Errorf("%s %s = %d %s, want %d problem: %s", tc.method, tc.path, rec.Code,
+					rec.Header().Get("Content-Type"), tc.status, rec.Body)
+			}
+		})
+	}
+	if p, _ := getPlant(t, h, "pothos"); p.CareLevel != "low" {
+		t.Errorf("rejected PATCHes changed pothos: careLevel %q", p.CareLevel)
+	}
+
+	// Only an oversized body is a 413; other read errors are the client's 400.
+	req := httptest.NewRequest(http.MethodPatch, "/api/plants/pothos", iotest.ErrReader(errors.New("connection reset")))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "connection reset") {
+		t.Errorf("failed body read = %d %s, want 400", rec.Code, rec.Body)
+	}
+}
+
+func TestAdminRetire(t *testing.T) {
+	h := newAdminServer(t)
+	rec := adminDo(t, h, http.MethodDelete, "/api/plants/pothos", "")
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Fatalf("DELETE = %d %q, want 204 and no body", rec.Code, rec.Body)
+	}
+	if p, code := getPlant(t, h, "pothos"); code != http.StatusOK || p.RetiredAt == nil {
+		t.Errorf("retired plant = %d %+v, want it with retiredAt", code, p)
+	}
+	if rec := adminDo(t, h, http.MethodDelete, "/api/plants/nope", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE of a missing plant = %d, want 404", rec.Code)
+	}
+
+	// PUT keeps the retirement; PATCH with retiredAt null lifts it.
+	put := strings.Replace(newPlant, `"ivy"`, `"pothos"`, 1)
+	if rec := adminDo(t, h, http.MethodPut, "/api/plants/pothos", put); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	if p, _ := getPlant(t, h, "pothos"); p.RetiredAt == nil {
+		t.Error("PUT un-retired pothos")
+	}
+	if rec := adminDo(t, h, http.MethodPatch, "/api/plants/pothos", `{"retiredAt": null}`); rec.Code != http.StatusOK {
+		t.Fatalf("PATCH = %d: %s", rec.Code, rec.Body)
+	}
+	if p, _ := getPlant(t, h, "pothos"); p.RetiredAt != nil {
+		t.Errorf("after PATCH retiredAt null: retiredAt = %v", p.RetiredAt)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name, token, auth string
+		status            int
+	}{
+		{"disabled", "", "Bearer " + testAdminToken, http.StatusForbidden},
+		{"missing token", testAdminToken, "", http.StatusUnauthorized},
+		{"wrong token", testAdminToken, "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", testAdminToken, "Basic " + testAdminToken, http.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.cfg.AdminToken = tc.token
+			h := s.routes()
+			req := httptest.NewRequest(http.MethodPost, "/api/plants", strings.NewReader(newPlant))
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d", rec.Code, tc.status)
+			}
+			if hasChallenge := rec.Header().Get("WWW-Authenticate") != ""; hasChallenge != (tc.status == http.StatusUnauthorized) {
+				t.Errorf("WWW-Authenticate = %q with status %d", rec.Header().Get("WWW-Authenticate"), rec.Code)
+			}
+			if _, code := getPlant(t, h, "ivy"); code != http.StatusNotFound {
+				t.Errorf("unauthorized POST created the plant")
+			}
+		})
+	}
+}
+
+func TestListPlantsPastLastPage(t *testing.T) {
+	h := newTestServer(t).routes()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/plants?page=5&perPage=3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var env struct {
+		Data  []models.Plant    `json:"data"`
+		Meta  meta              `json:"meta"`
+		Links map[string]string `json:"links"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Data == nil || len(env.Data) != 0 || env.Meta.Count != 0 || env.Meta.Total != 4 || env.Meta.Page != 5 {
+		t.Errorf("data %v, meta %+v; want an empty page of 4 plants", env.Data, env.Meta)
+	}
+	var rels []string
+	for rel := range env.Links {
+		rels = append(rels, rel)
+	}
+	slices.Sort(rels)
+	if !slices.Equal(rels, []string{"first", "last", "prev", "self"}) || env.Links["prev"] != env.Links["last"] ||
+		env.Links["last"] != "/api/v1/plants?page=2&perPage=3" {
+		t.Errorf("links = %v, want prev pointing at the last page and no next", env.Links)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/models"
+	"github.com/example/leaf-love-go/internal/recommend"
+	"github.com/example/leaf-love-go/internal/search"
+)
+
+// apiV1 is the path prefix of the versioned JSON API. Within a version,
+// fields are only ever added to responses, never renamed or removed.
+const apiV1 = "/api/v1"
+
+// envelope is the body of every successful /api/v1 response. Errors are
+// problem details, as elsewhere.
+type envelope struct {
+	Data  any               `json:"data"`
+	Meta  meta              `json:"meta"`
+	Links map[string]string `json:"links"` // rel → URL; always has self
+}
+
+// meta describes the data of an envelope. Count and Total are always
+// present; a single resource counts as one.
+type meta struct {
+	Count          int    `json:"count"`
+	Total          int    `json:"total"` // before paging or limits
+	CatalogVersion string `json:"catalogVersion"`
+
+	Page    int `json:"page,omitempty"`
+	PerPage int `json:"perPage,omitempty"`
+
+	Query       string                     `json:"query,omitempty"`
+	Preferences *models.PlantPreferences   `json:"preferences,omitempty"` // as applied
+	Facets      *recommend.Facets          `json:"facets,omitempty"`
+	Suggestions []recommend.Suggestion     `json:"suggestions,omitempty"`
+	NearMisses  []recommend.Recommendation `json:"nearMisses,omitempty"`
+}
+
+// writeEnvelope completes m with the catalog version and links with self,
+// and writes the envelope.
+func (s *server) writeEnvelope(w http.ResponseWriter, r *http.Request, data any, m meta, links map[string]string) {
+	info, err := s.repo.Info(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return
+	}
+	m.CatalogVersion = info.Version
+	if links == nil {
+		links = map[string]string{}
+	}
+	links["self"] = r.URL.RequestURI()
+	writeJSON(w, http.StatusOK, envelope{Data: data, Meta: m, Links: links})
+}
+
+// deprecated marks a legacy /api route as superseded by its /api/v1
+// twin, which takes the same path and parameters.
+func deprecated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		successor := apiV1 + strings.TrimPrefix(r.URL.Path, "/api")
+		if r.URL.RawQuery != "" {
+			successor += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Deprecation", "true")
+		w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+		h(w, r)
+	}
+}
+
+// handleV1Recommend serves GET and POST /api/v1/recommend. The
+// recommendations are the data; meta holds the applied preferences,
+// facets, near misses and, when nothing matches, suggestions.
+func (s *server) handleV1Recommend(w http.ResponseWriter, r *http.Request) {
+	prefs, err := decodePreferences(w, r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	recs, err := s.recommend(r, "api", prefs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return
+	}
+	ref, err := s.refine(r, prefs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return
+	}
+	s.writeEnvelope(w, r, recs, meta{
+		Count:       len(recs),
+		Total:       len(recs),
+		Preferences: &prefs,
+		Facets:      &ref.Facets,
+		Suggestions: ref.Suggestions,
+		NearMisses:  ref.NearMisses,
+	}, nil)
+}
+
+// handleV1Search serves GET /api/v1/search?q=...&limit=N.
+func (s *server) handleV1Search(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimSpace(r.URL.Query().Get("q")) == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	q, hits, total, err := s.runSearch(r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+	s.writeEnvelope(w, r, hits, meta{Count: len(hits), Total: total, Query: q},
+		map[string]string{"html": "/search?" + url.Values{"q": {q}}.Encode()})
+}
+
+// handleV1ListPlants serves GET /api/v1/plants?page=N&perPage=M with the
+// neighbouring pages in links.
+func (s *server) handleV1ListPlants(w http.ResponseWriter, r *http.Request) {
+	pg, ok := s.listPlants(w, r, apiV1+"/plants")
+	if !ok {
+		return
+	}
+	s.writeEnvelope(w, r, pg.Plants, meta{
+		Count:   len(pg.Plants),
+		Total:   pg.Total,
+		Page:    pg.Page,
+		PerPage: pg.PerPage,
+	}, pg.Links)
+}
+
+// handleV1GetPlant serves GET /api/v1/plants/{id}, retired plants included.
+func (s *server) handleV1GetPlant(w http.ResponseWriter, r *http.Request) {
+	plant, err := s.repo.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	s.writeEnvelope(w, r, plant, meta{Count: 1, Total: 1},
+		map[string]string{"html": "/plants/" + url.PathEscape(plant.ID)})
+}
+
+// handleV1LookupPlant serves GET /api/v1/plants/lookup?name=...
+func (s *server) handleV1LookupPlant(w http.ResponseWriter, r *http.Request) {
+	plants, ok := s.lookupPlants(w, r)
+	if !ok {
+		return
+	}
+	s.writeEnvelope(w, r, plants, meta{Count: len(plants), Total: len(plants)}, nil)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/data"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// newTestServer serves the small fixture catalog in testdata from memory.
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	store, err := data.NewStore("testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{started: time.Now(), catalog: store, repo: data.NewMemoryRepository(store)}
+	s.metrics = newServerMetrics(s)
+	return s
+}
+
+// TestAPIV1Contract pins the /api/v1 response bodies. After an intended
+// change, run go test ./cmd/server -run APIV1 -update and review the diff.
+func TestAPIV1Contract(t *testing.T) {
+	h := newTestServer(t).routes()
+	tests := []struct {
+		name, path  string
+		body        string // POSTed as application/json if set
+		status      int
+		contentType string
+	}{
+		{"recommend", "/api/v1/recommend?lightCondition=partial-shade,low-light&careLevel=low&location=indoor", "", http.StatusOK, "application/json"},
+		{"recommend-none", "/api/v1/recommend?lightCondition=low-light&careLevel=high&plantType=flowering&location=outdoor", "", http.StatusOK, "application/json"},
+		{"recommend-invalid", "/api/v1/recommend?careLevel=banana", "", http.StatusBadRequest, "application/problem+json"},
+		{"recommend-post", "/api/v1/recommend", `{"lightCondition": ["partial-shade", "low-light"], "careLevel": "low", "location": ["indoor"]}`, http.StatusOK, "application/json"},
+		{"recommend-post-unknown-field", "/api/v1/recommend", `{"colour": "green"}`, http.StatusBadRequest, "application/problem+json"},
+		{"plants", "/api/v1/plants?page=2&perPage=1", "", http.StatusOK, "application/json"},
+		{"plant", "/api/v1/plants/pothos", "", http.StatusOK, "application/json"},
+		{"plant-missing", "/api/v1/plants/nope", "", http.StatusNotFound, "application/problem+json"},
+		{"lookup", "/api/v1/plants/lookup?name=devils+ivy", "", http.StatusOK, "application/json"},
+		{"lookup-genus", "/api/v1/plants/lookup?name=Sansevieria", "", http.StatusOK, "application/json"},
+		{"search", "/api/v1/search?q=drought&limit=1", "", http.StatusOK, "application/json"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.body != "" {
+				req = httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d", rec.Code, tc.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tc.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tc.contentType)
+			}
+			var got bytes.Buffer
+			if err := json.Indent(&got, rec.Body.Bytes(), "", "  "); err != nil {
+				t.Fatalf("body is not JSON: %v\n%s", err, rec.Body)
+			}
+
+			golden := filepath.Join("testdata", "golden", tc.name+".json")
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("body differs from %s (run with -update to accept):\n%s", golden, got.String())
+			}
+		})
+	}
+}
+
+func TestPlantPageAliasRedirect(t *testing.T) {
+	h := newTestServer(t).routes()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/plants/devils-ivy", nil))
+	if rec.Code != http.StatusFound {
+		t.Errorf("status = %d, want 302", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/plants/pothos" {
+		t.Errorf("Location = %q, want /plants/pothos", loc)
+	}
+}
+
+func TestLegacyAPIDeprecated(t *testing.T) {
+	h := newTestServer(t).routes()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/recommend?careLevel=low", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q, want true", got)
+	}
+	if link := strings.Join(rec.Header().Values("Link"), ", "); !strings.Contains(link, `</api/v1/recommend?careLevel=low>; rel="successor-version"`) {
+		t.Errorf("Link = %q, want the v1 successor", link)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["results"]; !ok {
+		t.Errorf("legacy body lost its results field: %s", rec.Body)
+	}
+}
+
+// TestAPIErrorsAreProblems checks that errors raised outside the handlers,
+// by the mux, are problems too. Panics are covered by TestRecovery.
+func TestAPIErrorsAreProblems(t *testing.T) {
+	s := newTestServer(t)
+	s.cfg.AdminToken = "secret"
+	h := s.routes()
+	tests := []struct {
+		name, method, path, body string
+		status                   int
+		allow                    string
+		errors                   int // entries in the errors member
+	}{
+		{"unknown path", http.MethodGet, "/api/nope", "", http.StatusNotFound, "", 0},
+		{"unknown v1 path", http.MethodGet, "/api/v1/nope", "", http.StatusNotFound, "", 0},
+		{"wrong method", http.MethodDelete, "/api/recommend", "", http.StatusMethodNotAllowed, "GET, HEAD, POST", 0},
+		{"wrong method on v1", http.MethodPut, "/api/v1/plants/pothos", "", http.StatusMethodNotAllowed, "GET, HEAD", 0},
+		{"invalid plant", http.MethodPost, "/api/plants", `{"id": "Bad Id", "name": "Bad", "image": "bad.jpg", "lightCondition": ["dark"], "careLevel": "low", "plantType": "foliage", "location": "indoor", "size": "small"}`, http.StatusUnprocessableEntity, "", 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d", rec.Code, tc.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", ct)
+			}
+			if got := rec.Header().Get("Allow"); got != tc.allow {
+				t.Errorf("Allow = %q, want %q", got, tc.allow)
+			}
+			var p problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != tc.status || len(p.Errors) != tc.errors {
+				t.Errorf("problem = %+v, want status %d with %d errors", p, tc.status, tc.errors)
+			}
+		})
+	}
+}
+
+// TestRecommendBodyErrors checks that only an oversized body is a 413.
+func TestRecommendBodyErrors(t *testing.T) {
+	h := newTestServer(t).routes()
+	tests := []struct {
+		name   string
+		body   io.Reader
+		status int
+	}{
+		{"too large", strings.NewReader(`{"careLevel": "` + strings.Repeat("x", maxPreferencesBody) + `"}`), http.StatusRequestEntityTooLarge},
+		{"read failure", iotest.ErrReader(errors.New("connection reset")), http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/recommend", tc.body)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/example/leaf-love-go/internal/models"
+	"github.com/example/leaf-love-go/internal/recommend"
+)
+
+// defaultPreferences preselects the form for a first visit.
+var defaultPreferences = models.PlantPreferences{
+	LightCondition: models.Choices{"partial-shade"},
+	CareLevel:      models.Choices{"medium"},
+}
+
+// formOption is one checkbox of a preferences group.
+type formOption struct {
+	ID, Value, Label string
+	Count            int // plants recommended if only this option is chosen
+	Checked          bool
+	Disabled         bool // a dead end: choosing it recommends nothing
+}
+
+// formField is one checkbox group of the preferences form. Leaving a
+// group empty accepts any value.
+type formField struct {
+	Name, Label string
+	Options     []formOption
+}
+
+type choice struct{ value, label string }
+
+// preferenceForm describes the preferences form for prefs, with each
+// option labelled by its facet count. Options that would leave no
+// recommendations are disabled unless already checked.
+func preferenceForm(prefs models.PlantPreferences, f recommend.Facets) []formField {
+	field := func(name, label string, checked models.Choices, counts map[string]int, choices ...choice) formField {
+		ff := formField{Name: name, Label: label}
+		for _, c := range choices {
+			n := counts[c.value]
+			on := slices.Contains(checked, c.value)
+			ff.Options = append(ff.Options, formOption{
+				ID:       name + "-" + c.value,
+				Value:    c.value,
+				Label:    fmt.Sprintf("%s (%d)", c.label, n),
+				Count:    n,
+				Checked:  on,
+				Disabled: n == 0 && !on,
+			})
+		}
+		return ff
+	}
+	return []formField{
+		field("lightCondition", "Light Conditions", prefs.LightCondition, f.LightCondition,
+			choice{"partial-shade", "Partial shade"}, choice{"full-sun", "Full sun"}, choice{"low-light", "Low light"}),
+		field("careLevel", "Care Level", prefs.CareLevel, f.CareLevel,
+			choice{"low", "Low"}, choice{"medium", "Medium"}, choice{"high", "High"}),
+		field("plantType", "Plant Type", prefs.PlantType, f.PlantType,
+			choice{"foliage", "Foliage"}, choice{"flowering", "Flowering"}, choice{"succulent", "Succulent"}),
+		field("location", "Location", prefs.Location, f.Location,
+			choice{"indoor", "Indoor"}, choice{"outdoor", "Outdoor"}),
+		field("size", "Size", prefs.Size, f.Size,
+			choice{"small", "Small"}, choice{"medium", "Medium"}, choice{"large", "Large"}),
+	}
+}
+
+// preferencesValues encodes prefs as form values, repeating the
+// parameter of each multi-valued field.
+func preferencesValues(p models.PlantPreferences) url.Values {
+	v := url.Values{}
+	for name, vals := range map[string]models.Choices{
+		"lightCondition": p.LightCondition,
+		"careLevel":      p.CareLevel,
+		"plantType":      p.PlantType,
+		"location":       p.Location,
+		"size":           p.Size,
+	} {
+		if len(vals) > 0 {
+			v[name] = vals
+		}
+	}
+	return v
+}
+
+// suggestionView is a relaxation offered on the results page, submitted
+// as a form of hidden fields.
+type suggestionView struct {
+	Label  string
+	Plants int
+	Values url.Values
+}
+
+func suggestionViews(ss []recommend.Suggestion) []suggestionView {
+	out := make([]suggestionView, len(ss))
+	for i, s := range ss {
+		out[i] = suggestionView{Label: s.Label, Plants: s.Plants, Values: preferencesValues(s.Preferences)}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/models"
+	"github.com/example/leaf-love-go/internal/recommend"
+)
+
+func TestPreferenceFormDisablesDeadEnds(t *testing.T) {
+	plants := []models.Plant{
+		{ID: "aloe", Name: "Aloe", LightCondition: []string{"full-sun"}, CareLevel: "low",
+			PlantType: "succulent", Location: "indoor", Size: "small"},
+		{ID: "fern", Name: "Fern", LightCondition: []string{"low-light"}, CareLevel: "medium",
+			PlantType: "foliage", Location: "indoor", Size: "medium"},
+	}
+	// Succulents narrow the results to aloe, which low light cannot reach.
+	prefs := models.PlantPreferences{PlantType: models.Choices{"succulent"}}
+	form := preferenceForm(prefs, recommend.ComputeFacets(plants, prefs))
+
+	options := map[string]formOption{}
+	for _, f := range form {
+		for _, o := range f.Options {
+			options[o.ID] = o
+		}
+	}
+	tests := []struct {
+		id       string
+		count    int
+		checked  bool
+		disabled bool
+	}{
+		{"plantType-succulent", 1, true, false},
+		{"plantType-foliage", 1, false, false},
+		{"plantType-flowering", 0, false, true},
+		{"lightCondition-full-sun", 1, false, false},
+		{"lightCondition-low-light", 0, false, true},
+		{"size-large", 0, false, true},
+	}
+	for _, tc := range tests {
+		o, ok := options[tc.id]
+		if !ok {
+			t.Errorf("no option %s", tc.id)
+			continue
+		}
+		if o.Count != tc.count || o.Checked != tc.checked || o.Disabled != tc.disabled {
+			t.Errorf("%s = count %d, checked %v, disabled %v; want %d, %v, %v",
+				tc.id, o.Count, o.Checked, o.Disabled, tc.count, tc.checked, tc.disabled)
+		}
+	}
+
+	// A checked option stays enabled even when it leaves nothing.
+	prefs.LightCondition = models.Choices{"low-light"}
+	for _, f := range preferenceForm(prefs, recommend.ComputeFacets(plants, prefs)) {
+		for _, o := range f.Options {
+			if o.ID == "lightCondition-low-light" && (o.Count != 0 || o.Disabled) {
+				t.Errorf("checked dead end = count %d, disabled %v; want 0, enabled", o.Count, o.Disabled)
+			}
+		}
+	}
+}
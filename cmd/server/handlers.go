@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+	"github.com/example/leaf-love-go/internal/recommend"
+)
+
+// handleIndex renders the preferences form, preselected from the query
+// string if given, with each option showing how many plants it yields.
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	// The form always sends refine=1, so a refined form with every group
+	// left empty is told apart from a first visit.
+	prefs := defaultPreferences
+	if len(r.URL.Query()) > 0 {
+		prefs = preferencesFrom(r.URL.Query())
+	}
+	ref, err := s.refine(r, prefs)
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+	s.renderHTML(w, "index", map[string]any{"Fields": preferenceForm(prefs, ref.Facets)})
+}
+
+// handleRecommend renders recommendations for a submitted form.
+func (s *server) handleRecommend(w http.ResponseWriter, r *http.Request) {
+	prefs, err := decodePreferences(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	recs, err := s.recommend(r, "html", prefs)
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+	ref, err := s.refine(r, prefs)
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+	s.renderHTML(w, "results", map[string]any{
+		"Plants":      recs,
+		"Preferences": prefs,
+		"Count":       len(recs),
+		"Features":    ref.Facets.Features,
+		"Suggestions": suggestionViews(ref.Suggestions),
+		"NearMisses":  ref.NearMisses,
+		"Refine":      "/?refine=1&" + preferencesValues(prefs).Encode(),
+	})
+}
+
+// handleAPIRecommend serves, as JSON, recommendations for preferences
+// given in the query string or in a POSTed JSON body or form. Each
+// recommendation says why it matched. Facet counts and near misses come
+// with them, and, when nothing matches, suggestions for relaxing the
+// preferences.
+func (s *server) handleAPIRecommend(w http.ResponseWriter, r *http.Request) {
+	prefs, err := decodePreferences(w, r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	recs, err := s.recommend(r, "api", prefs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return
+	}
+	ref, err := s.refine(r, prefs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"results":     recs,
+		"facets":      ref.Facets,
+		"suggestions": ref.Suggestions,
+		"nearMisses":  ref.NearMisses,
+	})
+}
+
+// recommend loads the candidate plants for prefs and ranks them.
+// endpoint labels the result-size metrics.
+func (s *server) recommend(r *http.Request, endpoint string, prefs models.PlantPreferences) ([]recommend.Recommendation, error) {
+	candidates, err := s.repo.Find(r.Context(), recommend.Candidates(prefs))
+	if err != nil {
+		return nil, err
+	}
+	recs := recommend.Rank(candidates, prefs)
+	s.metrics.observeRecommendation(endpoint, len(recs))
+	infoFrom(r.Context()).results = len(recs)
+	return recs, nil
+}
+
+// nearMisses caps the near misses shown with recommendations.
+const nearMisses = 3
+
+// refinement is what helps a user adjust their preferences.
+type refinement struct {
+	Facets      recommend.Facets           `json:"facets"`
+	Suggestions []recommend.Suggestion     `json:"suggestions"` // when nothing matches
+	NearMisses  []recommend.Recommendation `json:"nearMisses"`  // left out, but scored at least NearMissMin
+}
+
+// refine computes the facet counts and near misses for prefs and, if it
+// gives no recommendations, suggestions for relaxing it. Unlike recommend
+// it needs every live plant, not just the candidates for prefs.
+func (s *server) refine(r *http.Request, prefs models.PlantPreferences) (refinement, error) {
+	plants, err := s.repo.Find(r.Context(), data.PlantFilter{})
+	if err != nil {
+		return refinement{}, err
+	}
+	ref := refinement{
+		Facets:      recommend.ComputeFacets(plants, prefs),
+		Suggestions: recommend.Suggest(plants, prefs),
+		NearMisses:  recommend.NearMisses(plants, prefs, nearMisses),
+	}
+	// Empty arrays rather than null keep the JSON shape stable.
+	if ref.Suggestions == nil {
+		ref.Suggestions = []recommend.Suggestion{}
+	}
+	if ref.NearMisses == nil {
+		ref.NearMisses = []recommend.Recommendation{}
+	}
+	return ref, nil
+}
+
+// handleHealth reports the catalog being served and whether the server is
+// ready for traffic. It answers 503 once shutdown has begun so load
+// balancers stop routing here while in-flight requests drain.
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if s.shuttingDown.Load() {
+		w.Header().Set("Connection", "close")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "shutting down", "ready": false})
+		return
+	}
+	info, err := s.repo.Info(r.Context())
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "storage unavailable: "+err.Error())
+		return
+	}
+	body := map[string]any{
+		"status":          "ok",
+		"ready":           true,
+		"catalogVersion":  info.Version,
+		"catalogPlants":   info.Plants,
+		"catalogLoadedAt": info.LoadedAt.UTC().Format(time.RFC3339),
+	}
+	if s.catalog != nil {
+		body["catalogError"] = s.catalog.LastError()
+	}
+	w.Header().Set("X-Catalog-Version", info.Version)
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.metrics.registry.Handler().ServeHTTP(w, r)
+}
+
+// catalogInfo reports the catalog being served, or a zero value if the
+// storage cannot be reached.
+func (s *server) catalogInfo() data.CatalogInfo {
+	info, _ := s.repo.Info(context.Background())
+	return info
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds each readiness check so one stuck dependency
+// cannot hang the probe.
+const checkTimeout = 2 * time.Second
+
+// healthCheck is one readiness dependency. A failing critical check makes
+// /readyz answer 503; other failures are reported but leave it ready.
+type healthCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// checkResult is one entry of the /readyz report.
+type checkResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"` // ok or fail
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// readinessChecks returns the checks /readyz runs for this server.
+func (s *server) readinessChecks() []healthCheck {
+	checks := []healthCheck{
+		{name: "storage", critical: true, check: func(ctx context.Context) error {
+			_, err := s.repo.Info(ctx)
+			return err
+		}},
+		{name: "catalog", critical: true, check: func(ctx context.Context) error {
+			info, err := s.repo.Info(ctx)
+			if err != nil {
+				return err
+			}
+			if info.Plants == 0 {
+				return errors.New("catalog is empty")
+			}
+			return nil
+		}},
+		{name: "templates", critical: true, check: func(ctx context.Context) error {
+			for _, name := range pages {
+				if _, err := s.views.get(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{name: "static", check: func(ctx context.Context) error {
+			_, err := fs.Stat(s.static, "styles.css")
+			return err
+		}},
+	}
+	if s.catalog != nil {
+		checks = append(checks, healthCheck{name: "catalog_reload", check: func(ctx context.Context) error {
+			if msg := s.catalog.LastError(); msg != "" {
+				return errors.New(msg)
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// runChecks runs every check concurrently and reports whether all
+// critical checks passed.
+func runChecks(ctx context.Context, checks []healthCheck) ([]checkResult, bool) {
+	results := make([]checkResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			begin := time.Now()
+			err := c.check(ctx)
+			res := checkResult{
+				Name:      c.name,
+				Status:    "ok",
+				Critical:  c.critical,
+				LatencyMS: float64(time.Since(begin).Microseconds()) / 1000,
+			}
+			if err != nil {
+				res.Status, res.Error = "fail", err.Error()
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	ready := true
+	for _, res := range results {
+		if res.Critical && res.Status != "ok" {
+			ready = false
+		}
+	}
+	return results, ready
+}
+
+// handleLiveness answers as long as the process can serve HTTP at all.
+func (s *server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":        "ok",
+		"uptimeSeconds": int(time.Since(s.started).Seconds()),
+	})
+}
+
+// handleReadiness runs the readiness checks and answers 503 if a critical
+// one fails or the server is shutting down.
+func (s *server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	results, ready := runChecks(r.Context(), s.checks)
+	status, code := "ok", http.StatusOK
+	if s.shuttingDown.Load() {
+		status, code = "shutting down", http.StatusServiceUnavailable
+	} else if !ready {
+		status, code = "fail", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{"status": status, "checks": results})
+}
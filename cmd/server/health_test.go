@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// TestShutdownDelay checks what a load balancer sees between the signal
+// and the drain: readiness and /health turn 503 at once, while liveness
+// and ordinary requests keep being served.
+func TestShutdownDelay(t *testing.T) {
+	s := newTestServer(t)
+	s.static = fstest.MapFS{"styles.css": {}}
+	// The test server has no views, so leave the templates check out.
+	s.checks = slices.DeleteFunc(s.readinessChecks(), func(c healthCheck) bool { return c.name == "templates" })
+	ts := httptest.NewServer(s.routes())
+	defer ts.Close()
+
+	get := func(path string) (int, map[string]any) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		return resp.StatusCode, body
+	}
+
+	if code, body := get("/readyz"); code != http.StatusOK || body["status"] != "ok" {
+		t.Fatalf("/readyz before shutdown = %d %v", code, body)
+	}
+	if code, body := get("/health"); code != http.StatusOK || body["ready"] != true {
+		t.Fatalf("/health before shutdown = %d %v", code, body)
+	}
+
+	s.shuttingDown.Store(true) // as main does on SIGTERM, before the delay
+	tests := []struct {
+		path   string
+		status int
+		check  func(map[string]any) bool
+	}{
+		{"/readyz", http.StatusServiceUnavailable, func(b map[string]any) bool { return b["status"] == "shutting down" }},
+		{"/health", http.StatusServiceUnavailable, func(b map[string]any) bool { return b["ready"] == false }},
+		{"/healthz", http.StatusOK, func(b map[string]any) bool { return b["status"] == "ok" }},
+		{"/api/v1/plants", http.StatusOK, func(b map[string]any) bool { return b["data"] != nil }},
+	}
+	for _, tc := range tests {
+		if code, body := get(tc.path); code != tc.status || !tc.check(body) {
+			t.Errorf("%s during the shutdown delay = %d %v, want %d", tc.path, code, body, tc.status)
+		}
+	}
+}
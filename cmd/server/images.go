@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/example/leaf-love-go/internal/assets"
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/imaging"
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// imageResolves reports whether a plant image can be served: it is either
+// an external URL, which we cannot check, or a known static asset.
+func imageResolves(m *assets.Manifest, image string) bool {
+	return strings.Contains(image, "://") || m.Has(image)
+}
+
+// missingImages returns the plants whose image does not resolve.
+func missingImages(m *assets.Manifest, plants []models.Plant) []models.Plant {
+	var missing []models.Plant
+	for _, p := range plants {
+		if !imageResolves(m, p.Image) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// reportMissingImages logs every plant whose image will be replaced by a
+// placeholder.
+func (s *server) reportMissingImages(ctx context.Context) {
+	plants, _, err := s.repo.List(ctx, 0, 0)
+	if err != nil {
+		logger(ctx).Warn("image check skipped", "err", err)
+		return
+	}
+	missing := missingImages(s.assets, plants)
+	for _, p := range missing {
+		logger(ctx).Warn("plant image not found, serving placeholder", "plant", p.ID, "image", p.Image)
+	}
+	if len(missing) > 0 {
+		logger(ctx).Warn("plants with missing images", "missing", len(missing), "plants", len(plants))
+	}
+}
+
+// plantImg is what the "plantImage" template helper returns: everything
+// an <img> tag needs to load the right size without layout shift.
+type plantImg struct {
+	Src           string
+	Srcset        string // empty unless resized variants exist
+	Width, Height int
+}
+
+// cardVariants are offered in srcset for plant cards; they share the
+// card aspect ratio.
+var cardVariants = []string{"thumb", "card"}
+
+// plantImage is the "plantImage" template helper. Raster images in
+// web/static are served as resized card variants; anything else is linked
+// as is, and a missing image falls back to the generated placeholder.
+func (s *server) plantImage(id, image string) plantImg {
+	card, _ := imaging.Lookup("card")
+	img := plantImg{Width: card.Width, Height: card.Height}
+	switch {
+	case !imageResolves(s.assets, image):
+		img.Src = "/plants/" + url.PathEscape(id) + "/placeholder.svg"
+	case strings.Contains(image, "://") || !resizable(image):
+		img.Src = s.assets.URL(image)
+	default:
+		var set []string
+		for _, name := range cardVariants {
+			v, _ := imaging.Lookup(name)
+			set = append(set, fmt.Sprintf("%s %dw", s.imageVariantURL(image, v), v.Width))
+		}
+		img.Src, img.Srcset = s.imageVariantURL(image, card), strings.Join(set, ", ")
+	}
+	return img
+}
+
+// resizable reports whether the image format can be resized.
+func resizable(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
+
+// imageVariantURL links a static image resized to v. The fingerprinted
+// name is used when there is one, so the variant is cacheable forever.
+func (s *server) imageVariantURL(image string, v imaging.Variant) string {
+	return "/images/" + v.Name + "/" + strings.TrimPrefix(s.assets.URL(image), "/static/")
+}
+
+// handleImage serves GET /images/{variant}/{name...}: a static image
+// resized to a named variant. Results are cached in the image cache
+// directory, keyed by content hash, so they survive restarts.
+func (s *server) handleImage(w http.ResponseWriter, r *http.Request) {
+	v, ok := imaging.Lookup(r.PathValue("variant"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	a, hashed, ok := s.assets.Resolve(r.PathValue("name"))
+	if !ok || !resizable(a.Name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := s.imageVariant(a, v)
+	if err != nil {
+		logger(r.Context()).Error("resize image", "image", a.Name, "variant", v.Name, "err", err)
+		http.Error(w, "cannot resize image", http.StatusInternalServerError)
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Type", "image/jpeg")
+	h.Set("ETag", `"`+a.Sum[:32]+"-"+v.Name+`"`)
+	if hashed {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// imageVariant returns a resized to v from the disk cache, rendering and
+// storing it on a miss. Renders are serialised: they are CPU-heavy and a
+// burst of requests for a new image should decode it once.
+func (s *server) imageVariant(a *assets.Asset, v imaging.Variant) ([]byte, error) {
+	var file string
+	if dir := s.cfg.ImageCacheDir; dir != "" {
+		file = filepath.Join(dir, a.Sum[:16]+"-"+v.Name+".jpg")
+		if b, err := os.ReadFile(file); err == nil {
+			return b, nil
+		}
+	}
+
+	s.resizeMu.Lock()
+	defer s.resizeMu.Unlock()
+	if file != "" {
+		if b, err := os.ReadFile(file); err == nil {
+			return b, nil // rendered while we waited
+		}
+	}
+	b, err := imaging.RenderBytes(a.Bytes(), v)
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		if err := writeFileAtomic(file, b); err != nil {
+			slog.Warn("image cache write failed", "file", file, "err", err)
+		}
+	}
+	return b, nil
+}
+
+// writeFileAtomic writes b to a temporary file next to name and renames
+// it into place, so readers never see a partial file.
+func writeFileAtomic(name string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// typeColours are the placeholder background per plant type.
+var typeColours = map[string]string{
+	"flowering": "#9b3d63",
+	"foliage":   "#2f6e4a",
+	"succulent": "#2d6f73",
+}
+
+var placeholderSVG = template.Must(template.New("placeholder").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 400 300" width="400" height="300" role="img" aria-label="{{.Name}}">` +
+		`<rect width="400" height="300" fill="{{.Colour}}"/>` +
+		`<text x="200" y="150" dy=".35em" text-anchor="middle" font-family="system-ui, sans-serif" font-size="96" font-weight="600" fill="#ffffffcc">{{.Initials}}</text>` +
+		`</svg>`))
+
+// handlePlaceholder serves GET /plants/{id}/placeholder.svg: the plant's
+// initials on its type colour, standing in for a missing photo.
+func (s *server) handlePlaceholder(w http.ResponseWriter, r *http.Request) {
+	plant, err := s.repo.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, data.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+	colour, ok := typeColours[plant.PlantType]
+	if !ok {
+		colour = "#3b4a50"
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	err = placeholderSVG.Execute(w, map[string]string{
+		"Name":     plant.Name,
+		"Colour":   colour,
+		"Initials": initials(plant.Name),
+	})
+	if err != nil {
+		logger(r.Context()).Error("render placeholder", "plant", plant.ID, "err", err)
+	}
+}
+
+// initials returns the first letter of up to two words of name, upper-cased.
+func initials(name string) string {
+	words := strings.Fields(name)
+	var b strings.Builder
+	for _, word := range words[:min(2, len(words))] {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "?"
+	}
+	return b.String()
+}
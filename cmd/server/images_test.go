@@ -0,0 +1,42 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/assets"
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/web"
+)
+
+// knownMissingImages are catalog plants that have no photo in web/static
+// yet and are shown with a placeholder. Remove an entry when its photo is
+// added; the test fails if the list and the tree disagree either way.
+var knownMissingImages = []string{
+	"aloe-vera", "bonsai", "hibiscus", "jade-plant",
+	"lavender", "orchid", "rose-bush", "sunflower",
+}
+
+func TestCatalogImagesMatchAssets(t *testing.T) {
+	store, err := data.NewStore("../../catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := assets.Build(web.Static(), "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var missing []string
+	for _, p := range missingImages(m, store.Snapshot().Plants) {
+		missing = append(missing, p.ID)
+		if !slices.Contains(knownMissingImages, p.ID) {
+			t.Errorf("plant %s: image %s is not in web/static", p.ID, p.Image)
+		}
+	}
+	for _, id := range knownMissingImages {
+		if !slices.Contains(missing, id) {
+			t.Errorf("plant %s: listed in knownMissingImages but its image now resolves (or the plant is gone); remove it from the list", id)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// newLogger builds the process logger. format is "json" or "text"; level
+// is one of debug, info, warn, error; file is a path to append to, or ""
+// for stdout. The returned closer releases the file.
+func newLogger(format, level, file string) (*slog.Logger, io.Closer, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, nil, fmt.Errorf("log level %q: want debug, info, warn or error", level)
+	}
+
+	var out io.WriteCloser = nopCloser{os.Stdout}
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open log file: %w", err)
+		}
+		out = f
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		h = slog.NewJSONHandler(out, opts)
+	case "text":
+		h = slog.NewTextHandler(out, opts)
+	default:
+		out.Close()
+		return nil, nil, fmt.Errorf("log format %q: want json or text", format)
+	}
+	return slog.New(h), out, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// logger returns the request-scoped logger, tagged with the request ID.
+func logger(ctx context.Context) *slog.Logger {
+	if l := infoFrom(ctx).log; l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// fatal logs msg at error level and exits; for startup failures.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/metrics"
+)
+
+// serverMetrics are the metrics the server records itself.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests       *metrics.Counter
+	httpRequests   metrics.CounterVec   // route, method, status
+	httpDuration   metrics.HistogramVec // route, method
+	inFlight       *metrics.Gauge
+	recResults     metrics.HistogramVec // endpoint
+	recZeroResults metrics.CounterVec   // endpoint
+}
+
+// resultBuckets suit result counts of a catalog of tens to hundreds of plants.
+var resultBuckets = []float64{0, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+func newServerMetrics(s *server) *serverMetrics {
+	r := metrics.NewRegistry()
+	m := &serverMetrics{
+		registry: r,
+		requests: r.NewCounter("leaflove_requests_total", "Total HTTP requests."),
+		httpRequests: r.NewCounterVec("leaflove_http_requests_total",
+			"HTTP requests by route, method and status code.", "route", "method", "status"),
+		httpDuration: r.NewHistogramVec("leaflove_http_request_duration_seconds",
+			"HTTP request latency by route and method.", metrics.DefBuckets, "route", "method"),
+		inFlight: r.NewGauge("leaflove_http_requests_in_flight", "HTTP requests currently being served."),
+		recResults: r.NewHistogramVec("leaflove_recommendation_results",
+			"Plants returned per recommendation request.", resultBuckets, "endpoint"),
+		recZeroResults: r.NewCounterVec("leaflove_recommendation_zero_results_total",
+			"Recommendation requests that returned no plants.", "endpoint"),
+	}
+	// Export the recommendation series from the start so alerts on the
+	// zero-results rate have a baseline before the first empty page.
+	for _, endpoint := range []string{"html", "api"} {
+		m.recResults.With(endpoint)
+		m.recZeroResults.With(endpoint)
+	}
+
+	r.NewGaugeFunc("leaflove_uptime_seconds", "Process uptime in seconds.", nil, func() []metrics.Sample {
+		return []metrics.Sample{{Value: time.Since(s.started).Seconds()}}
+	})
+	r.NewGaugeFunc("leaflove_catalog_info", "Catalog snapshot currently served.", []string{"version"}, func() []metrics.Sample {
+		return []metrics.Sample{{LabelValues: []string{s.catalogInfo().Version}, Value: 1}}
+	})
+	r.NewGaugeFunc("leaflove_catalog_plants", "Plants in the current catalog snapshot.", nil, func() []metrics.Sample {
+		return []metrics.Sample{{Value: float64(s.catalogInfo().Plants)}}
+	})
+	r.NewGaugeFunc("leaflove_catalog_loaded_timestamp_seconds", "When the current catalog snapshot was loaded.", nil, func() []metrics.Sample {
+		return []metrics.Sample{{Value: float64(s.catalogInfo().LoadedAt.Unix())}}
+	})
+	r.NewCounterFunc("leaflove_catalog_reload_failures_total", "Catalog reloads rejected by validation.", nil, func() []metrics.Sample {
+		var failures uint64
+		if s.catalog != nil {
+			failures = s.catalog.Failures()
+		}
+		return []metrics.Sample{{Value: float64(failures)}}
+	})
+	return m
+}
+
+// knownMethods are the methods counted under their own name; the rest are
+// "other", so clients cannot mint series with made-up methods.
+var knownMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// observeRequest records one finished HTTP request.
+func (m *serverMetrics) observeRequest(route, method string, status int, d time.Duration) {
+	if route == "" {
+		route = "unmatched" // keep unknown paths out of the label space
+	}
+	if !slices.Contains(knownMethods, method) {
+		method = "other"
+	}
+	m.requests.Inc()
+	m.httpRequests.With(route, method, strconv.Itoa(status)).Inc()
+	m.httpDuration.With(route, method).Observe(d.Seconds())
+}
+
+// observeRecommendation records the size of one recommendation result.
+func (m *serverMetrics) observeRecommendation(endpoint string, results int) {
+	m.recResults.With(endpoint).Observe(float64(results))
+	if results == 0 {
+		m.recZeroResults.With(endpoint).Inc()
+	}
+}
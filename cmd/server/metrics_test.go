@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsMethodLabel(t *testing.T) {
+	h := newTestServer(t).routes()
+	for _, method := range []string{http.MethodGet, "BREW", "X-" + strings.Repeat("A", 40)} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/api/v1/plants", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`leaflove_http_requests_total{route="GET /api/v1/plants",method="GET",status="200"} 1`,
+		`leaflove_http_requests_total{route="/api/",method="other",status="405"} 2`,
+		`leaflove_http_request_duration_seconds_count{route="/api/",method="other"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition lacks %s", want)
+		}
+	}
+	if strings.Contains(body, "BREW") || strings.Contains(body, "X-AAAA") {
+		t.Errorf("unknown method leaked into a label:\n%s", body)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// middleware wraps a handler with cross-cutting behaviour.
+type middleware func(http.Handler) http.Handler
+
+// chain applies mws to h so that the first middleware is the outermost.
+func chain(h http.Handler, mws ...middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// requestInfo is shared by the middleware chain through the request
+// context. Inner layers fill it in; outer layers read it once the request
+// has been served.
+type requestInfo struct {
+	id      string
+	log     *slog.Logger // tagged with id
+	route   string       // matched mux pattern, empty if nothing matched
+	results int          // plants recommended, -1 if not a recommendation
+}
+
+type ctxKey struct{}
+
+func infoFrom(ctx context.Context) *requestInfo {
+	if info, ok := ctx.Value(ctxKey{}).(*requestInfo); ok {
+		return info
+	}
+	return &requestInfo{results: -1}
+}
+
+// withRequestID gives every request an ID, reusing a sane incoming
+// X-Request-ID, and echoes it in the response.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		info := &requestInfo{id: id, log: slog.Default().With("request_id", id), results: -1}
+		ctx := context.WithValue(r.Context(), ctxKey{}, info)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// withRoute records the mux pattern that matched, for logs and metrics.
+func withRoute(pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		infoFrom(r.Context()).route = pattern
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder captures the status code and body size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter { return rec.ResponseWriter }
+
+// Status returns the status sent, defaulting to 200 if the handler wrote nothing.
+func (rec *statusRecorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// recorder returns w as a *statusRecorder, wrapping it if needed, so
+// several middlewares share one recorder.
+func recorder(w http.ResponseWriter) *statusRecorder {
+	if rec, ok := w.(*statusRecorder); ok {
+		return rec
+	}
+	return &statusRecorder{ResponseWriter: w}
+}
+
+// withLogging writes one structured access log record per request.
+// Server errors are logged at error level, everything else at info.
+func withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recorder(w)
+		begin := time.Now()
+		next.ServeHTTP(rec, r)
+
+		info := infoFrom(r.Context())
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", info.route),
+			slog.Int("status", rec.Status()),
+			slog.Int("bytes", rec.bytes),
+			slog.Float64("latency_ms", float64(time.Since(begin).Microseconds())/1000),
+		}
+		if info.results >= 0 {
+			attrs = append(attrs, slog.Int("recommendations", info.results))
+		}
+		level := slog.LevelInfo
+		if rec.Status() >= 500 {
+			level = slog.LevelError
+		}
+		logger(r.Context()).LogAttrs(r.Context(), level, "request", attrs...)
+	})
+}
+
+// withMetrics records request counts, latency and concurrency.
+func (s *server) withMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recorder(w)
+		s.metrics.inFlight.Inc()
+		defer s.metrics.inFlight.Dec()
+		begin := time.Now()
+		next.ServeHTTP(rec, r)
+		s.metrics.observeRequest(infoFrom(r.Context()).route, r.Method, rec.Status(), time.Since(begin))
+	})
+}
+
+// withRecovery turns a panicking handler into a 500, a problem under
+// /api/, and logs the stack.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recorder(w)
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger(r.Context()).Error("panic serving request",
+					"method", r.Method, "path", r.URL.Path, "panic", fmt.Sprint(v), "stack", string(debug.Stack()))
+				switch {
+				case rec.status != 0:
+				case strings.HasPrefix(r.URL.Path, "/api/"):
+					writeError(rec, http.StatusInternalServerError, "internal server error")
+				default:
+					http.Error(rec, "internal server error", http.StatusInternalServerError)
+				}
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	generated := regexp.MustCompile(`^[0-9a-f]{16}$`)
+	tests := []struct {
+		name, incoming string
+		reused         bool
+	}{
+		{"none", "", false},
+		{"sane", "req-42_abc.DEF", true},
+		{"longest allowed", strings.Repeat("a", 64), true},
+		{"too long", strings.Repeat("a", 65), false},
+		{"bad characters", "id with spaces", false},
+		{"header injection", "abc\r\nSet-Cookie: x=y", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = infoFrom(r.Context()).id
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.incoming != "" {
+				req.Header["X-Request-Id"] = []string{tc.incoming}
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("X-Request-ID")
+			if got != seen {
+				t.Errorf("response ID %q differs from the handler's %q", got, seen)
+			}
+			if tc.reused && got != tc.incoming {
+				t.Errorf("ID = %q, want the incoming %q", got, tc.incoming)
+			}
+			if !tc.reused && !generated.MatchString(got) {
+				t.Errorf("ID = %q, want a generated 16 hex digit ID", got)
+			}
+		})
+	}
+
+	// Generated IDs differ per request.
+	h := withRequestID(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	ids := map[string]bool{}
+	for range 10 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		ids[rec.Header().Get("X-Request-ID")] = true
+	}
+	if len(ids) != 10 {
+		t.Errorf("10 requests got %d distinct IDs", len(ids))
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	boom := withRecovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+	tests := []struct {
+		path, contentType string
+	}{
+		{"/api/v1/plants", "application/problem+json"},
+		{"/api/plants/pothos", "application/problem+json"},
+		{"/plants/pothos", "text/plain; charset=utf-8"},
+		{"/apiary", "text/plain; charset=utf-8"},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		boom.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != http.StatusInternalServerError || rec.Header().Get("Content-Type") != tc.contentType {
+			t.Errorf("%s = %d %s, want 500 %s", tc.path, rec.Code, rec.Header().Get("Content-Type"), tc.contentType)
+			continue
+		}
+		if tc.contentType == "application/problem+json" {
+			var p problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil || p.Status != http.StatusInternalServerError ||
+				p.Detail != "internal server error" {
+				t.Errorf("%s: problem = %+v, %v", tc.path, p, err)
+			}
+		} else if rec.Body.String() != "internal server error\n" {
+			t.Errorf("%s: body = %q", tc.path, rec.Body)
+		}
+	}
+
+	// A handler that already answered keeps its status; nothing is appended.
+	late := withRecovery(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("late")
+	}))
+	rec := httptest.NewRecorder()
+	late.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/plants", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "partial" {
+		t.Errorf("panic after writing = %d %q, want 202 \"partial\"", rec.Code, rec.Body)
+	}
+
+	// http.ErrAbortHandler is passed on for net/http to abort the response.
+	abort := withRecovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) }))
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Error("ErrAbortHandler was swallowed")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+	"github.com/example/leaf-love-go/internal/recommend"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+	similarPlants  = 3
+)
+
+// handlePlantPage renders /plants/{id}: the full plant plus similar plants.
+// An unknown ID that is another name of exactly one plant, such as
+// /plants/devils-ivy, redirects to that plant.
+func (s *server) handlePlantPage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	plant, err := s.repo.Get(r.Context(), id)
+	if errors.Is(err, data.ErrNotFound) {
+		if ix, err := s.searchIndex(r.Context()); err == nil {
+			if found := ix.Lookup(id); len(found) == 1 {
+				http.Redirect(w, r, "/plants/"+url.PathEscape(found[0].ID), http.StatusFound)
+				return
+			}
+		}
+		s.renderHTMLStatus(w, http.StatusNotFound, "notfound", map[string]any{"ID": id})
+		return
+	}
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+
+	others, err := s.repo.Find(r.Context(), data.PlantFilter{})
+	if err != nil {
+		http.Error(w, "storage error", http.StatusInternalServerError)
+		return
+	}
+	s.renderHTML(w, "plant", map[string]any{
+		"Plant":   plant,
+		"Similar": recommend.Similar(plant, others, similarPlants),
+	})
+}
+
+// handleGetPlant serves GET /api/plants/{id}. Retired plants are still
+// returned, with retiredAt set.
+func (s *server) handleGetPlant(w http.ResponseWriter, r *http.Request) {
+	plant, err := s.repo.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plant)
+}
+
+// handleLookupPlant serves GET /api/plants/lookup?name=... as a JSON array.
+func (s *server) handleLookupPlant(w http.ResponseWriter, r *http.Request) {
+	plants, ok := s.lookupPlants(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, plants)
+}
+
+// lookupPlants finds the plants known by the name query parameter as ID,
+// common name, alias, scientific name or synonym. Several plants mean the
+// name is ambiguous. It writes an error response and returns ok=false if
+// there are none.
+func (s *server) lookupPlants(w http.ResponseWriter, r *http.Request) (plants []models.Plant, ok bool) {
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return nil, false
+	}
+	ix, err := s.searchIndex(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return nil, false
+	}
+	plants = ix.Lookup(name)
+	if len(plants) == 0 {
+		writeError(w, http.StatusNotFound, "no plant is known by that name")
+		return nil, false
+	}
+	return plants, true
+}
+
+// handleListPlants serves GET /api/plants?page=N&perPage=M as a JSON array.
+// The total is sent in X-Total-Count and neighbouring pages in Link.
+func (s *server) handleListPlants(w http.ResponseWriter, r *http.Request) {
+	pg, ok := s.listPlants(w, r, "/api/plants")
+	if !ok {
+		return
+	}
+	var links []string
+	for _, rel := range []string{"first", "last", "prev", "next"} {
+		if u, ok := pg.Links[rel]; ok {
+			links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u, rel))
+		}
+	}
+	w.Header().Add("Link", strings.Join(links, ", "))
+	w.Header().Set("X-Total-Count", strconv.Itoa(pg.Total))
+	writeJSON(w, http.StatusOK, pg.Plants)
+}
+
+// plantPage is one page of the plant listing.
+type plantPage struct {
+	Plants               []models.Plant
+	Total, Page, PerPage int
+	Links                map[string]string // first, last and, if any, prev and next
+}
+
+// listPlants reads the page and perPage query parameters and loads that
+// page, linking neighbouring pages under base. It writes an error
+// response and returns ok=false if the request cannot be served.
+func (s *server) listPlants(w http.ResponseWriter, r *http.Request, base string) (pg plantPage, ok bool) {
+	q := r.URL.Query()
+	page, err := queryInt(q, "page", 1, 1, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return pg, false
+	}
+	perPage, err := queryInt(q, "perPage", defaultPerPage, 1, maxPerPage)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return pg, false
+	}
+
+	plants, total, err := s.repo.List(r.Context(), (page-1)*perPage, perPage)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "storage error")
+		return pg, false
+	}
+	if plants == nil {
+		plants = []models.Plant{}
+	}
+
+	last := max((total+perPage-1)/perPage, 1)
+	link := func(n int) string {
+		v := url.Values{"page": {strconv.Itoa(n)}, "perPage": {strconv.Itoa(perPage)}}
+		return base + "?" + v.Encode()
+	}
+	links := map[string]string{"first": link(1), "last": link(last)}
+	if page > 1 {
+		links["prev"] = link(min(page-1, last))
+	}
+	if page < last {
+		links["next"] = link(page + 1)
+	}
+	return plantPage{Plants: plants, Total: total, Page: page, PerPage: perPage, Links: links}, true
+}
+
+// queryInt reads an integer query parameter within [lo, hi]; hi <= 0 means
+// no upper bound.
+func queryInt(q url.Values, name string, def, lo, hi int) (int, error) {
+	s := q.Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || (hi > 0 && n > hi) {
+		if hi > 0 {
+			return 0, fmt.Errorf("%s must be an integer between %d and %d", name, lo, hi)
+		}
+		return 0, fmt.Errorf("%s must be an integer of at least %d", name, lo)
+	}
+	return n, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// maxPreferencesBody caps a JSON or form preferences body; a real one is a
+// few hundred bytes.
+const maxPreferencesBody = 8 << 10
+
+// requestError is a request the server cannot serve, with the status to
+// answer it with.
+type requestError struct {
+	status int
+	msg    string
+}
+
+func (e *requestError) Error() string { return e.msg }
+
+// errorStatus is the HTTP status for an error from decodePreferences or
+// runSearch.
+func errorStatus(err error) int {
+	var re *requestError
+	if errors.As(err, &re) {
+		return re.status
+	}
+	return http.StatusBadRequest
+}
+
+// decodePreferences reads and validates the preferences of a request,
+// however they were sent: as a JSON body (application/json), a form body
+// (urlencoded or multipart) or the query string. Fields of a JSON body
+// are strings or arrays of strings; unknown fields are rejected. Errors
+// are a *requestError or a *models.ValidationError.
+func decodePreferences(w http.ResponseWriter, r *http.Request) (models.PlantPreferences, error) {
+	var prefs models.PlantPreferences
+	ctype := r.Header.Get("Content-Type")
+	switch mt, _, _ := mime.ParseMediaType(ctype); {
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		prefs = preferencesFrom(r.URL.Query())
+	case mt == "application/json":
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPreferencesBody))
+		if err != nil {
+			return prefs, bodyError(err, "reading request body: ")
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&prefs); err != nil {
+			return prefs, &requestError{http.StatusBadRequest, "invalid preferences JSON: " + err.Error()}
+		}
+		if dec.More() {
+			return prefs, &requestError{http.StatusBadRequest, "invalid preferences JSON: unexpected data after the object"}
+		}
+		prefs = normalized(prefs)
+	case mt == "application/x-www-form-urlencoded" || mt == "multipart/form-data" || ctype == "":
+		r.Body = http.MaxBytesReader(w, r.Body, maxPreferencesBody)
+		var err error
+		if mt == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxPreferencesBody)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			return prefs, bodyError(err, "invalid form: ")
+		}
+		prefs = preferencesFrom(r.Form)
+	default:
+		return prefs, &requestError{http.StatusUnsupportedMediaType,
+			fmt.Sprintf("unsupported Content-Type %q: send application/json or a form", ctype)}
+	}
+	if err := prefs.Validate(); err != nil {
+		return prefs, err
+	}
+	return prefs, nil
+}
+
+// bodyError is the error for a failure reading a request body: 413 if it
+// is over the limit of its http.MaxBytesReader, otherwise 400 with prefix
+// and err.
+func bodyError(err error, prefix string) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return &requestError{http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)}
+	}
+	return &requestError{http.StatusBadRequest, prefix + err.Error()}
+}
+
+// preferencesFrom reads preferences from a query string or form. A field
+// may be repeated (careLevel=low&careLevel=medium) or hold a comma list
+// (careLevel=low,medium).
+func preferencesFrom(v url.Values) models.PlantPreferences {
+	return models.PlantPreferences{
+		LightCondition: choicesFrom(v["lightCondition"]),
+		CareLevel:      choicesFrom(v["careLevel"]),
+		PlantType:      choicesFrom(v["plantType"]),
+		Location:       choicesFrom(v["location"]),
+		Size:           choicesFrom(v["size"]),
+	}
+}
+
+// normalized applies the query string rules to decoded JSON preferences,
+// so "low,medium" and ["low", "medium"] mean the same.
+func normalized(p models.PlantPreferences) models.PlantPreferences {
+	return models.PlantPreferences{
+		LightCondition: choicesFrom(p.LightCondition),
+		CareLevel:      choicesFrom(p.CareLevel),
+		PlantType:      choicesFrom(p.PlantType),
+		Location:       choicesFrom(p.Location),
+		Size:           choicesFrom(p.Size),
+	}
+}
+
+// choicesFrom splits comma lists and drops empty and repeated values.
+func choicesFrom(vs []string) models.Choices {
+	var out models.Choices
+	for _, v := range vs {
+		for _, c := range strings.Split(v, ",") {
+			if c = strings.TrimSpace(c); c != "" && !slices.Contains(out, c) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
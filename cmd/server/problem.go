@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// Problem types beyond the generic "about:blank", whose meaning is the
+// HTTP status alone.
+const (
+	problemInvalidPreferences = "/problems/invalid-preferences"
+	problemInvalidPlant       = "/problems/invalid-plant"
+)
+
+// problem is an RFC 7807 problem details object, the body of every error
+// response from the JSON API.
+type problem struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Detail string              `json:"detail,omitempty"`
+	Errors []models.FieldError `json:"errors,omitempty"` // invalid-preferences and invalid-plant only
+}
+
+func writeProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// writeError answers a JSON endpoint with a generic problem for status.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeProblem(w, problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: detail})
+}
+
+// writeRequestError answers a request whose input could not be used:
+// invalid preferences get their own problem type listing every bad value,
+// anything else a generic problem with errorStatus.
+func writeRequestError(w http.ResponseWriter, err error) {
+	var ve *models.ValidationError
+	if !errors.As(err, &ve) {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	writeProblem(w, problem{
+		Type:   problemInvalidPreferences,
+		Title:  "Invalid preferences",
+		Status: http.StatusBadRequest,
+		Detail: ve.Error(),
+		Errors: ve.Errors,
+	})
+}
+
+// apiMethods are the methods probed to tell a wrong method from a wrong
+// path under /api/.
+var apiMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// apiFallback answers /api/ requests that no other route of mux takes, as
+// a problem rather than the mux's plain-text error: 405 with an Allow
+// header if the path has routes for other methods, 404 otherwise.
+func apiFallback(mux *http.ServeMux) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allow []string
+		for _, m := range apiMethods {
+			probe := r.Clone(r.Context())
+			probe.Method = m
+			if _, pattern := mux.Handler(probe); pattern != "" && pattern != "/api/" {
+				allow = append(allow, m)
+			}
+		}
+		if len(allow) == 0 {
+			writeError(w, http.StatusNotFound, "no such endpoint: "+r.URL.Path)
+			return
+		}
+		w.Header().Set("Allow", strings.Join(allow, ", "))
+		writeError(w, http.StatusMethodNotAllowed,
+			fmt.Sprintf("%s is not allowed on %s; use %s", r.Method, r.URL.Path, strings.Join(allow, ", ")))
+	}
+}
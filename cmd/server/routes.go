@@ -0,0 +1,49 @@
+package main
+
+import "net/http"
+
+// routes registers every endpoint with its method and path pattern and
+// wraps the mux in the middleware chain.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, withRoute(pattern, h))
+	}
+
+	handle("GET /{$}", s.handleIndex)
+	handle("POST /recommend", s.handleRecommend)
+	handle("GET /search", s.handleSearch)
+	handle("GET /plants/{id}", s.handlePlantPage)
+	handle("GET /plants/{id}/placeholder.svg", s.handlePlaceholder)
+
+	handle("GET /api/v1/recommend", s.handleV1Recommend)
+	handle("POST /api/v1/recommend", s.handleV1Recommend)
+	handle("GET /api/v1/search", s.handleV1Search)
+	handle("GET /api/v1/plants", s.handleV1ListPlants)
+	handle("GET /api/v1/plants/lookup", s.handleV1LookupPlant)
+	handle("GET /api/v1/plants/{id}", s.handleV1GetPlant)
+
+	// Unversioned read routes predate /api/v1 and are kept for existing
+	// clients. The admin API has no v1 twin yet.
+	handle("GET /api/recommend", deprecated(s.handleAPIRecommend))
+	handle("POST /api/recommend", deprecated(s.handleAPIRecommend))
+	handle("GET /api/search", deprecated(s.handleAPISearch))
+	handle("GET /api/plants", deprecated(s.handleListPlants))
+	handle("GET /api/plants/lookup", deprecated(s.handleLookupPlant))
+	handle("GET /api/plants/{id}", deprecated(s.handleGetPlant))
+	handle("POST /api/plants", s.requireAdmin(s.handleCreatePlant))
+	handle("PUT /api/plants/{id}", s.requireAdmin(s.handleReplacePlant))
+	handle("PATCH /api/plants/{id}", s.requireAdmin(s.handlePatchPlant))
+	handle("DELETE /api/plants/{id}", s.requireAdmin(s.handleRetirePlant))
+	handle("/api/", apiFallback(mux))
+
+	handle("GET /health", s.handleHealth)
+	handle("GET /healthz", s.handleLiveness)
+	handle("GET /readyz", s.handleReadiness)
+	handle("GET /metrics", s.handleMetrics)
+
+	handle("GET /images/{variant}/{name...}", s.handleImage)
+	mux.Handle("GET /static/", withRoute("GET /static/", http.StripPrefix("/static/", s.assets)))
+
+	return chain(mux, withRequestID, withLogging, s.withMetrics, withRecovery)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/search"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// searchCache is the index for one catalog version.
+type searchCache struct {
+	version string
+	index   *search.Index
+}
+
+// searchIndex returns an index over the plants currently served. It is
+// rebuilt lazily whenever the catalog version changes, which covers both
+// hot reloads and admin edits.
+func (s *server) searchIndex(ctx context.Context) (*search.Index, error) {
+	info, err := s.repo.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c := s.search.Load(); c != nil && c.version == info.Version {
+		return c.index, nil
+	}
+	plants, _, err := s.repo.List(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	ix := search.New(plants)
+	s.search.Store(&searchCache{version: info.Version, index: ix})
+	return ix, nil
+}
+
+// runSearch searches for the q query parameter and returns up to limit
+// hits and the number found before the limit. Errors are a *requestError
+// carrying the status to answer with, so the API can write a problem and
+// the search page a plain error.
+func (s *server) runSearch(r *http.Request) (q string, hits []search.Hit, total int, err error) {
+	q = strings.TrimSpace(r.URL.Query().Get("q"))
+	limit, err := queryInt(r.URL.Query(), "limit", defaultSearchLimit, 1, maxSearchLimit)
+	if err != nil {
+		return q, nil, 0, &requestError{http.StatusBadRequest, err.Error()}
+	}
+	ix, err := s.searchIndex(r.Context())
+	if err != nil {
+		return q, nil, 0, &requestError{http.StatusInternalServerError, "storage error"}
+	}
+	hits = ix.Search(q, 0)
+	return q, hits[:min(limit, len(hits))], len(hits), nil
+}
+
+// handleAPISearch serves GET /api/search?q=...&limit=N as a JSON array of
+// hits, best first. Highlights are HTML with matches wrapped in <mark>.
+func (s *server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimSpace(r.URL.Query().Get("q")) == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	_, hits, _, err := s.runSearch(r)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+	writeJSON(w, http.StatusOK, hits)
+}
+
+// searchResult is a hit prepared for the search page.
+type searchResult struct {
+	search.Hit
+	Name, ScientificName, Snippet template.HTML
+	AlsoKnownAs                   template.HTML // matched aliases or synonyms
+}
+
+// handleSearch renders GET /search?q=... for the search box.
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q, hits, _, err := s.runSearch(r)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	results := make([]searchResult, 0, len(hits))
+	for _, h := range hits {
+		// Highlights are already escaped by the search package.
+		res := searchResult{
+			Hit:            h,
+			Name:           template.HTML(template.HTMLEscapeString(h.Plant.Name)),
+			ScientificName: template.HTML(template.HTMLEscapeString(h.Plant.ScientificName)),
+		}
+		if v, ok := h.Highlights["name"]; ok {
+			res.Name = template.HTML(v)
+		}
+		if v, ok := h.Highlights["scientificName"]; ok {
+			res.ScientificName = template.HTML(v)
+		}
+		for _, f := range []string{"aliases", "synonyms"} {
+			if v, ok := h.Highlights[f]; ok {
+				res.AlsoKnownAs = template.HTML(v)
+				break
+			}
+		}
+		for _, f := range []string{"description", "features"} {
+			if v, ok := h.Highlights[f]; ok {
+				res.Snippet = template.HTML(v)
+				break
+			}
+		}
+		results = append(results, res)
+	}
+	s.renderHTML(w, "search", map[string]any{"Query": q, "Results": results})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSearchErrors checks that a bad search is a problem from the API but
+// a plain error from the search page.
+func TestSearchErrors(t *testing.T) {
+	h := newTestServer(t).routes()
+	tests := []struct {
+		path, contentType string
+	}{
+		{"/api/v1/search?q=fern&limit=0", "application/problem+json"},
+		{"/api/search?q=fern&limit=500", "application/problem+json"},
+		{"/search?q=fern&limit=0", "text/plain; charset=utf-8"},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != tc.contentType {
+			t.Errorf("%s = %d %s, want 400 %s", tc.path, rec.Code, rec.Header().Get("Content-Type"), tc.contentType)
+		}
+		if !strings.Contains(rec.Body.String(), "limit must be an integer between 1 and 100") {
+			t.Errorf("%s: body %q does not explain the limit", tc.path, rec.Body)
+		}
+	}
+}
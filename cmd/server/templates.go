@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// pages lists the templates in web/templates, without the .html suffix.
+// "layout" wraps every other page.
+var pages = []string{"layout", "index", "results", "plant", "notfound", "search"}
+
+// views parses page templates from a file system. Normally they are
+// parsed once at startup; in dev mode they are re-parsed on every render
+// so edits on disk show up without a restart.
+type views struct {
+	fsys   fs.FS
+	live   bool
+	funcs  template.FuncMap
+	parsed map[string]*template.Template // read-only after newViews
+}
+
+// newViews parses every page up front, so a broken template fails
+// startup even in dev mode.
+func newViews(fsys fs.FS, live bool, funcs template.FuncMap) (*views, error) {
+	v := &views{fsys: fsys, live: live, funcs: funcs, parsed: make(map[string]*template.Template)}
+	for _, name := range pages {
+		t, err := v.parse(name)
+		if err != nil {
+			return nil, err
+		}
+		v.parsed[name] = t
+	}
+	return v, nil
+}
+
+func (v *views) parse(name string) (*template.Template, error) {
+	t, err := template.New(name+".html").Funcs(v.funcs).ParseFS(v.fsys, name+".html")
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// get returns the named page template.
+func (v *views) get(name string) (*template.Template, error) {
+	if v.live {
+		return v.parse(name)
+	}
+	t, ok := v.parsed[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s: not defined", name)
+	}
+	return t, nil
+}
+
+// renderHTML renders a page template inside the layout with status 200.
+func (s *server) renderHTML(w http.ResponseWriter, page string, data any) {
+	s.renderHTMLStatus(w, http.StatusOK, page, data)
+}
+
+// renderHTMLStatus renders a page template inside the layout. The page is
+// rendered to a buffer first so a template error can still become a 500.
+func (s *server) renderHTMLStatus(w http.ResponseWriter, status int, page string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	t, err := s.views.get(page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	layout, err := s.views.get("layout")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		http.Error(w, "template error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	if err := layout.Execute(w, map[string]any{"Content": template.HTML(sb.String())}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
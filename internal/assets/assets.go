@@ -0,0 +1,207 @@
+// Package assets serves static files with content-hashed URLs. A
+// Manifest fingerprints every file (monstera.jpg is published as
+// monstera.3f2a1c9d.jpg) so hashed URLs can be cached forever, and
+// answers conditional requests with strong ETags.
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Asset is one static file and its precomputed variants.
+type Asset struct {
+	Name   string // logical name, e.g. "css/site.css"
+	Hashed string // fingerprinted name, e.g. "css/site.3f2a1c9d.css"
+	ETag   string // strong, quoted
+	Sum    string // hex SHA-256 of the content
+
+	data []byte
+	gzip []byte // nil unless compressible and smaller than data
+}
+
+// compressible lists the extensions worth gzipping; images are already
+// compressed.
+var compressible = map[string]bool{".css": true, ".js": true, ".svg": true, ".json": true, ".txt": true}
+
+const (
+	hashLen      = 8 // hex digits of the content hash used in file names
+	cacheForever = "public, max-age=31536000, immutable"
+	revalidate   = "no-cache"
+)
+
+// Manifest maps logical asset names to fingerprinted ones and serves both.
+type Manifest struct {
+	prefix   string // URL path the handler is mounted at, e.g. "/static/"
+	fsys     fs.FS
+	live     bool
+	byName   map[string]*Asset
+	byHashed map[string]*Asset
+}
+
+// Build reads every file in fsys and fingerprints it. URLs are returned
+// under prefix.
+func Build(fsys fs.FS, prefix string) (*Manifest, error) {
+	m := &Manifest{prefix: prefix, fsys: fsys, byName: make(map[string]*Asset), byHashed: make(map[string]*Asset)}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		a, err := m.load(name)
+		if err != nil {
+			return err
+		}
+		m.byName[a.Name] = a
+		m.byHashed[a.Hashed] = a
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Live returns a manifest for development: files are read from fsys on
+// every request, URLs are not fingerprinted and nothing is cached for long.
+func Live(fsys fs.FS, prefix string) *Manifest {
+	return &Manifest{prefix: prefix, fsys: fsys, live: true}
+}
+
+func (m *Manifest) load(name string) (*Asset, error) {
+	data, err := fs.ReadFile(m.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	ext := path.Ext(name)
+	a := &Asset{
+		Name:   name,
+		Hashed: strings.TrimSuffix(name, ext) + "." + hash[:hashLen] + ext,
+		ETag:   `"` + hash[:32] + `"`,
+		Sum:    hash,
+		data:   data,
+	}
+	if m.live {
+		return a, nil
+	}
+	if compressible[ext] {
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		zw.Write(data)
+		zw.Close()
+		if buf.Len() < len(data) {
+			a.gzip = buf.Bytes()
+		}
+	}
+	return a, nil
+}
+
+// Lookup returns the asset with the given logical name.
+func (m *Manifest) Lookup(name string) (*Asset, bool) {
+	if m.live {
+		a, err := m.load(name)
+		return a, err == nil
+	}
+	a, ok := m.byName[name]
+	return a, ok
+}
+
+// Bytes returns the asset content. Callers must not modify it.
+func (a *Asset) Bytes() []byte { return a.data }
+
+// Resolve finds an asset by hashed or logical name (without the mount
+// prefix) and reports which kind of name it was.
+func (m *Manifest) Resolve(name string) (a *Asset, hashed, ok bool) {
+	if a, ok := m.byHashed[name]; ok {
+		return a, true, true
+	}
+	a, ok = m.Lookup(name)
+	return a, false, ok
+}
+
+// Has reports whether name, with or without the mount prefix, is a
+// known asset.
+func (m *Manifest) Has(name string) bool {
+	if !strings.HasPrefix(name, m.prefix) && strings.HasPrefix(name, "/") {
+		return false
+	}
+	_, ok := m.Lookup(strings.TrimPrefix(name, m.prefix))
+	return ok
+}
+
+// URL returns the URL to link to for an asset. Names may carry the mount
+// prefix ("/static/monstera.jpg") or not ("monstera.jpg"); other absolute
+// paths and external URLs are returned unchanged.
+func (m *Manifest) URL(name string) string {
+	logical, ok := strings.CutPrefix(name, m.prefix)
+	if !ok && (strings.HasPrefix(name, "/") || strings.Contains(name, "://")) {
+		return name
+	}
+	if a, ok := m.byName[logical]; ok && !m.live {
+		return m.prefix + a.Hashed
+	}
+	return m.prefix + logical
+}
+
+// ServeHTTP serves an asset by logical or hashed name; mount it behind
+// http.StripPrefix. Hashed names are cacheable forever, logical names must
+// be revalidated with the ETag.
+func (m *Manifest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a, hashed, ok := m.Resolve(strings.TrimPrefix(r.URL.Path, "/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	cache := revalidate
+	if hashed {
+		cache = cacheForever
+	}
+
+	h := w.Header()
+	h.Set("Cache-Control", cache)
+	if ctype := mime.TypeByExtension(path.Ext(a.Name)); ctype != "" {
+		h.Set("Content-Type", ctype)
+	}
+	body, etag := a.data, a.ETag
+	if a.gzip != nil {
+		h.Add("Vary", "Accept-Encoding")
+		if acceptsEncoding(r, "gzip") {
+			body, etag = a.gzip, variantETag(etag, "gz")
+			h.Set("Content-Encoding", "gzip")
+		}
+	}
+	h.Set("ETag", etag)
+	// ServeContent answers If-None-Match with 304 and handles ranges.
+	http.ServeContent(w, r, a.Name, time.Time{}, bytes.NewReader(body))
+}
+
+// variantETag gives each encoding its own strong ETag, as their bytes differ.
+func variantETag(etag, enc string) string {
+	return strings.TrimSuffix(etag, `"`) + "-" + enc + `"`
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header allows enc.
+func acceptsEncoding(r *http.Request, enc string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), enc) {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(q, 64)
+			return err == nil && v > 0
+		}
+		return true
+	}
+	return false
+}
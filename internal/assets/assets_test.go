@@ -0,0 +1,194 @@
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// css is large and repetitive enough that gzip pays off.
+var css = []byte(strings.Repeat(".leaf { color: green; }\n", 50))
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"css/site.css":    {Data: css},
+		"tiny.css":        {Data: []byte("a{}")},
+		"monstera.jpg":    {Data: []byte("\xff\xd8\xff not really a jpeg")},
+		"css/site.css.br": {Data: []byte("stale brotli")},
+	}
+}
+
+func build(t *testing.T) *Manifest {
+	t.Helper()
+	m, err := Build(testFS(), "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// get serves path (under the mount prefix) with the given request headers.
+func get(m *Manifest, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	http.StripPrefix("/static", m).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBuildFingerprints(t *testing.T) {
+	m := build(t)
+	a, ok := m.Lookup("css/site.css")
+	if !ok {
+		t.Fatal("css/site.css not found")
+	}
+	sum := sha256.Sum256(css)
+	hash := hex.EncodeToString(sum[:])
+	if a.Sum != hash || a.ETag != `"`+hash[:32]+`"` || a.Hashed != "css/site."+hash[:8]+".css" {
+		t.Errorf("asset = %+v, want it named and tagged by its hash %s", a, hash)
+	}
+	if !bytes.Equal(a.Bytes(), css) {
+		t.Error("Bytes differ from the file")
+	}
+	if _, ok := m.Lookup("css/site.css.br"); !ok {
+		t.Error("a .br file is an asset like any other")
+	}
+
+	tests := []struct{ name, want string }{
+		{"css/site.css", "/static/" + a.Hashed},
+		{"/static/css/site.css", "/static/" + a.Hashed},
+		{"/static/missing.css", "/static/missing.css"},
+		{"/images/card/monstera.jpg", "/images/card/monstera.jpg"},
+		{"https://example.com/x.css", "https://example.com/x.css"},
+	}
+	for _, tc := range tests {
+		if got := m.URL(tc.name); got != tc.want {
+			t.Errorf("URL(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if !m.Has("/static/monstera.jpg") || !m.Has("monstera.jpg") || m.Has("/other/monstera.jpg") || m.Has("/static/nope.jpg") {
+		t.Error("Has disagrees with the manifest")
+	}
+}
+
+func TestServeCaching(t *testing.T) {
+	m := build(t)
+	a, _ := m.Lookup("monstera.jpg")
+	tests := []struct {
+		name, path string
+		cache      string
+	}{
+		{"hashed", "/static/" + a.Hashed, cacheForever},
+		{"logical", "/static/monstera.jpg", revalidate},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := get(m, tc.path)
+			if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), a.Bytes()) {
+				t.Fatalf("status %d, %d bytes", rec.Code, rec.Body.Len())
+			}
+			h := rec.Header()
+			if h.Get("Cache-Control") != tc.cache || h.Get("ETag") != a.ETag || h.Get("Content-Type") != "image/jpeg" {
+				t.Errorf("headers = %v", h)
+			}
+			if h.Get("Vary") != "" || h.Get("Content-Encoding") != "" {
+				t.Errorf("an image was negotiated: %v", h)
+			}
+
+			rec = get(m, tc.path, "If-None-Match", a.ETag)
+			if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+				t.Errorf("If-None-Match = %d with %d bytes, want 304 and no body", rec.Code, rec.Body.Len())
+			}
+			if rec := get(m, tc.path, "If-None-Match", `"other"`); rec.Code != http.StatusOK {
+				t.Errorf("stale If-None-Match = %d, want 200", rec.Code)
+			}
+		})
+	}
+	if rec := get(m, "/static/nope.jpg"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown asset = %d, want 404", rec.Code)
+	}
+}
+
+func TestServeGzip(t *testing.T) {
+	m := build(t)
+	a, _ := m.Lookup("css/site.css")
+	gzETag := variantETag(a.ETag, "gz")
+
+	tests := []struct {
+		name, accept string
+		gzipped      bool
+	}{
+		{"none", "", false},
+		{"gzip", "gzip", true},
+		{"gzip among others", "br;q=1.0, gzip;q=0.8, deflate", true},
+		{"gzip refused", "gzip;q=0, deflate", false},
+		{"brotli only", "br", false},
+		{"case-insensitive", "GZip", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := get(m, "/static/css/site.css", "Accept-Encoding", tc.accept)
+			h := rec.Header()
+			if h.Get("Vary") != "Accept-Encoding" || h.Get("Content-Type") != "text/css; charset=utf-8" {
+				t.Errorf("headers = %v", h)
+			}
+			body := rec.Body.Bytes()
+			if !tc.gzipped {
+				if h.Get("Content-Encoding") != "" || h.Get("ETag") != a.ETag || !bytes.Equal(body, css) {
+					t.Errorf("want identity: Content-Encoding %q, ETag %s, %d bytes", h.Get("Content-Encoding"), h.Get("ETag"), len(body))
+				}
+				return
+			}
+			if h.Get("Content-Encoding") != "gzip" || h.Get("ETag") != gzETag || len(body) >= len(css) {
+				t.Fatalf("want gzip: Content-Encoding %q, ETag %s, %d bytes", h.Get("Content-Encoding"), h.Get("ETag"), len(body))
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plain, err := io.ReadAll(zr); err != nil || !bytes.Equal(plain, css) {
+				t.Errorf("gunzipped body differs: %v", err)
+			}
+		})
+	}
+
+	// Each encoding revalidates against its own ETag.
+	if rec := get(m, "/static/css/site.css", "Accept-Encoding", "gzip", "If-None-Match", gzETag); rec.Code != http.StatusNotModified {
+		t.Errorf("gzip ETag with gzip = %d, want 304", rec.Code)
+	}
+	if rec := get(m, "/static/css/site.css", "Accept-Encoding", "gzip", "If-None-Match", a.ETag); rec.Code != http.StatusOK {
+		t.Errorf("identity ETag with gzip = %d, want 200", rec.Code)
+	}
+
+	// Gzip would not make tiny.css smaller, so it is never negotiated.
+	rec := get(m, "/static/tiny.css", "Accept-Encoding", "gzip")
+	if rec.Header().Get("Content-Encoding") != "" || rec.Header().Get("Vary") != "" || rec.Body.String() != "a{}" {
+		t.Errorf("tiny.css = %v %q, want it as is", rec.Header(), rec.Body)
+	}
+}
+
+func TestLive(t *testing.T) {
+	fsys := testFS()
+	m := Live(fsys, "/static/")
+	if got := m.URL("css/site.css"); got != "/static/css/site.css" {
+		t.Errorf("URL = %q, want no fingerprint in dev", got)
+	}
+	fsys["css/site.css"] = &fstest.MapFile{Data: []byte("body{}")}
+	rec := get(m, "/static/css/site.css", "Accept-Encoding", "gzip")
+	if rec.Body.String() != "body{}" || rec.Header().Get("Cache-Control") != revalidate || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("live asset = %v %q, want the file as it is now, uncompressed", rec.Header(), rec.Body)
+	}
+	if !regexp.MustCompile(`^"[0-9a-f]{32}"$`).MatchString(rec.Header().Get("ETag")) {
+		t.Errorf("ETag = %q", rec.Header().Get("ETag"))
+	}
+}
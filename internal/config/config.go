@@ -0,0 +1,284 @@
+// Package config loads the server configuration. Each setting is resolved
+// from, in increasing priority: built-in defaults, a JSON config file,
+// environment variables and command-line flags.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the effective server configuration.
+type Config struct {
+	Addr    string `json:"addr"`
+	TLSCert string `json:"tlsCert"`
+	TLSKey  string `json:"tlsKey"`
+
+	Dev                 bool     `json:"dev"`           // serve templates and static files from disk
+	StaticDir           string   `json:"staticDir"`     // dev mode only
+	TemplatesDir        string   `json:"templatesDir"`  // dev mode only
+	ImageCacheDir       string   `json:"imageCacheDir"` // resized images; empty disables the disk cache
+	CatalogPath         string   `json:"catalogPath"`
+	CatalogPollInterval Duration `json:"catalogPollInterval"` // 0 disables hot reload
+
+	Store      string `json:"store"` // memory or sqlite
+	SQLitePath string `json:"sqlitePath"`
+
+	LogFormat string `json:"logFormat"` // json or text
+	LogLevel  string `json:"logLevel"`
+	LogFile   string `json:"logFile"` // empty logs to stdout
+
+	ReadTimeout       Duration `json:"readTimeout"`
+	ReadHeaderTimeout Duration `json:"readHeaderTimeout"`
+	WriteTimeout      Duration `json:"writeTimeout"`
+	IdleTimeout       Duration `json:"idleTimeout"`
+	ShutdownDelay     Duration `json:"shutdownDelay"`   // serve not-ready this long before draining
+	ShutdownTimeout   Duration `json:"shutdownTimeout"` // drain deadline on SIGINT/SIGTERM
+	MaxHeaderBytes    int      `json:"maxHeaderBytes"`
+
+	AdminToken string `json:"adminToken"` // secret; empty disables the admin API
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Addr:                ":8080",
+		StaticDir:           "web/static",
+		TemplatesDir:        "web/templates",
+		ImageCacheDir:       filepath.Join(os.TempDir(), "leaflove-images"),
+		CatalogPath:         "catalog",
+		CatalogPollInterval: Duration(2 * time.Second),
+		Store:               "memory",
+		SQLitePath:          "leaflove.db",
+		LogFormat:           "json",
+		LogLevel:            "info",
+		ReadTimeout:         Duration(15 * time.Second),
+		ReadHeaderTimeout:   Duration(5 * time.Second),
+		WriteTimeout:        Duration(30 * time.Second),
+		IdleTimeout:         Duration(2 * time.Minute),
+		ShutdownDelay:       Duration(5 * time.Second),
+		ShutdownTimeout:     Duration(20 * time.Second),
+		MaxHeaderBytes:      64 << 10,
+	}
+}
+
+// setting binds one Config field to its flag and environment variable.
+type setting struct {
+	flag, env, usage string
+	value            flag.Value
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"addr", "LISTEN_ADDR", "address to listen on", (*stringValue)(&c.Addr)},
+		{"tls-cert", "TLS_CERT_FILE", "TLS certificate file; enables HTTPS together with -tls-key", (*stringValue)(&c.TLSCert)},
+		{"tls-key", "TLS_KEY_FILE", "TLS private key file", (*stringValue)(&c.TLSKey)},
+		{"dev", "DEV_MODE", "serve templates and static files from disk, re-parsing templates on every request", (*boolValue)(&c.Dev)},
+		{"static-dir", "STATIC_DIR", "directory served under /static/ in dev mode", (*stringValue)(&c.StaticDir)},
+		{"templates-dir", "TEMPLATES_DIR", "page template directory in dev mode", (*stringValue)(&c.TemplatesDir)},
+		{"image-cache-dir", "IMAGE_CACHE_DIR", "directory caching resized images (empty disables)", (*stringValue)(&c.ImageCacheDir)},
+		{"catalog", "CATALOG_PATH", "catalog directory or file", (*stringValue)(&c.CatalogPath)},
+		{"catalog-poll", "CATALOG_POLL_INTERVAL", "catalog reload poll interval (0 disables)", &c.CatalogPollInterval},
+		{"store", "PLANT_STORE", "plant storage backend: memory or sqlite", (*stringValue)(&c.Store)},
+		{"sqlite-path", "SQLITE_PATH", "SQLite database file", (*stringValue)(&c.SQLitePath)},
+		{"log-format", "LOG_FORMAT", "log format: json or text", (*stringValue)(&c.LogFormat)},
+		{"log-level", "LOG_LEVEL", "log level: debug, info, warn or error", (*stringValue)(&c.LogLevel)},
+		{"log-file", "LOG_FILE", "file to append logs to (default stdout)", (*stringValue)(&c.LogFile)},
+		{"read-timeout", "READ_TIMEOUT", "maximum time to read a whole request", &c.ReadTimeout},
+		{"read-header-timeout", "READ_HEADER_TIMEOUT", "maximum time to read request headers", &c.ReadHeaderTimeout},
+		{"write-timeout", "WRITE_TIMEOUT", "maximum time to write a response", &c.WriteTimeout},
+		{"idle-timeout", "IDLE_TIMEOUT", "how long idle keep-alive connections are kept", &c.IdleTimeout},
+		{"shutdown-delay", "SHUTDOWN_DELAY", "how long to keep serving, reporting not ready, before draining on SIGINT/SIGTERM", &c.ShutdownDelay},
+		{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "how long to drain in-flight requests on SIGINT/SIGTERM", &c.ShutdownTimeout},
+		{"max-header-bytes", "MAX_HEADER_BYTES", "maximum size of request headers in bytes", (*intValue)(&c.MaxHeaderBytes)},
+		{"admin-token", "ADMIN_TOKEN", "bearer token for the admin API (empty disables it)", (*stringValue)(&c.AdminToken)},
+	}
+}
+
+// Load resolves the configuration from args (without the program name)
+// and lookupEnv, which is os.LookupEnv outside tests. A variable that is
+// set overrides the config file even when empty, so IMAGE_CACHE_DIR= turns
+// the cache off. The config file is named by -config or CONFIG_FILE. The
+// result is validated; flag.ErrHelp is returned if -h was given.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (Config, error) {
+	// First pass: check the flags and find the config file.
+	scratch := Default()
+	fs, file := flagSet(&scratch, output)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	path := *file
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, s := range cfg.settings() {
+		if v, ok := lookupEnv(s.env); ok {
+			if err := s.value.Set(v); err != nil {
+				return Config{}, fmt.Errorf("%s: %w", s.env, err)
+			}
+		}
+	}
+	// Second pass: flags given explicitly override everything else.
+	fs, _ = flagSet(&cfg, io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+func flagSet(c *Config, output io.Writer) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(output)
+	file := fs.String("config", "", "JSON config file (or CONFIG_FILE)")
+	for _, s := range c.settings() {
+		fs.Var(s.value, s.flag, fmt.Sprintf("%s (or %s)", s.usage, s.env))
+	}
+	return fs, file
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting, not just the first.
+func (c Config) Validate() error {
+	var errs []error
+	oneOf := func(name, v string, allowed ...string) {
+		if !slices.Contains(allowed, v) {
+			errs = append(errs, fmt.Errorf("%s: %q is not one of %s", name, v, strings.Join(allowed, ", ")))
+		}
+	}
+
+	if c.Addr == "" {
+		errs = append(errs, errors.New("addr: required"))
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		errs = append(errs, errors.New("tls-cert and tls-key must be set together"))
+	}
+	if c.Dev {
+		for _, d := range []struct{ name, path string }{{"static-dir", c.StaticDir}, {"templates-dir", c.TemplatesDir}} {
+			if fi, err := os.Stat(d.path); err != nil || !fi.IsDir() {
+				errs = append(errs, fmt.Errorf("%s: %q is not a directory", d.name, d.path))
+			}
+		}
+	}
+	if c.CatalogPath == "" {
+		errs = append(errs, errors.New("catalog: required"))
+	}
+	oneOf("store", c.Store, "memory", "sqlite")
+	if c.Store == "sqlite" && c.SQLitePath == "" {
+		errs = append(errs, errors.New("sqlite-path: required with store=sqlite"))
+	}
+	oneOf("log-format", c.LogFormat, "json", "text")
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("log-level: %q is not one of debug, info, warn, error", c.LogLevel))
+	}
+	for _, s := range c.settings() {
+		if d, ok := s.value.(*Duration); ok && *d < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", s.flag))
+		}
+	}
+	if c.MaxHeaderBytes < 1<<10 {
+		errs = append(errs, fmt.Errorf("max-header-bytes: %d is below the 1024 byte minimum", c.MaxHeaderBytes))
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of c that is safe to print.
+func (c Config) Redacted() Config {
+	if c.AdminToken != "" {
+		c.AdminToken = "REDACTED"
+	}
+	return c
+}
+
+// LogValue logs the redacted configuration as a group of its settings.
+func (c Config) LogValue() slog.Value {
+	r := c.Redacted()
+	var attrs []slog.Attr
+	for _, s := range r.settings() {
+		attrs = append(attrs, slog.String(s.flag, s.value.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Duration is a time.Duration written as "1m30s" in config files and flags.
+type Duration time.Duration
+
+// D returns d as a time.Duration.
+func (d Duration) D() time.Duration { return time.Duration(d) }
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalText(b []byte) error { return d.Set(string(b)) }
+
+type stringValue string
+
+func (v *stringValue) String() string     { return string(*v) }
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", s)
+	}
+	*v = intValue(n)
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string   { return strconv.FormatBool(bool(*v)) }
+func (v *boolValue) IsBoolFlag() bool { return true }
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("%q is not a boolean", s)
+	}
+	*v = boolValue(b)
+	return nil
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env is a lookupEnv over a fixed set of variables.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := vars[k]
+		return v, ok
+	}
+}
+
+// writeFile writes a config file to a fresh directory and returns its path.
+func writeFile(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, `{"addr": ":9000", "logLevel": "warn", "shutdownDelay": "1s",
+		"imageCacheDir": "/var/cache/leaflove", "adminToken": "from-file"}`)
+	other := writeFile(t, `{"addr": ":9001"}`)
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want func(*Config) // applied to Default() to get the expected config
+	}{
+		{"defaults", nil, nil, func(*Config) {}},
+		{"file over defaults", []string{"-config", file}, nil, func(c *Config) {
+			c.Addr, c.LogLevel, c.ShutdownDelay = ":9000", "warn", Duration(time.Second)
+			c.ImageCacheDir, c.AdminToken = "/var/cache/leaflove", "from-file"
+		}},
+		{"file named by CONFIG_FILE", nil, map[string]string{"CONFIG_FILE": other}, func(c *Config) {
+			c.Addr = ":9001"
+		}},
+		{"-config over CONFIG_FILE", []string{"-config", other}, map[string]string{"CONFIG_FILE": file}, func(c *Config) {
+			c.Addr = ":9001"
+		}},
+		{"env over file", []string{"-config", file}, map[string]string{"LISTEN_ADDR": ":9100", "SHUTDOWN_DELAY": "3s"}, func(c *Config) {
+			c.Addr, c.LogLevel, c.ShutdownDelay = ":9100", "warn", Duration(3*time.Second)
+			c.ImageCacheDir, c.AdminToken = "/var/cache/leaflove", "from-file"
+		}},
+		{"flags over env", []string{"-config", file, "-addr", ":9200", "-shutdown-delay", "0s"},
+			map[string]string{"LISTEN_ADDR": ":9100", "SHUTDOWN_DELAY": "3s"}, func(c *Config) {
+				c.Addr, c.LogLevel, c.ShutdownDelay = ":9200", "warn", 0
+				c.ImageCacheDir, c.AdminToken = "/var/cache/leaflove", "from-file"
+			}},
+		{"empty env clears the file", []string{"-config", file}, map[string]string{"IMAGE_CACHE_DIR": "", "ADMIN_TOKEN": ""}, func(c *Config) {
+			c.Addr, c.LogLevel, c.ShutdownDelay = ":9000", "warn", Duration(time.Second)
+			c.ImageCacheDir = ""
+		}},
+		{"empty flag clears env", []string{"-image-cache-dir="}, map[string]string{"IMAGE_CACHE_DIR": "/tmp/x"}, func(c *Config) {
+			c.ImageCacheDir = ""
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Load(tc.args, env(tc.env), io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Default()
+			tc.want(&want)
+			if got != want {
+				t.Errorf("config =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{"bad env value", nil, map[string]string{"SHUTDOWN_DELAY": "soon"}, "SHUTDOWN_DELAY:"},
+		{"empty typed env value", nil, map[string]string{"MAX_HEADER_BYTES": ""}, "MAX_HEADER_BYTES:"},
+		{"bad flag", []string{"-dev=maybe"}, nil, `"maybe" is not a boolean`},
+		{"missing file", []string{"-config", filepath.Join(t.TempDir(), "nope.json")}, nil, "config file:"},
+		{"unknown file key", []string{"-config", writeFile(t, `{"colour": "green"}`)}, nil, `unknown field "colour"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.args, env(tc.env), io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load = %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+
+	if _, err := Load([]string{"-h"}, env(nil), io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	c := Default()
+	c.Addr = ""
+	c.TLSCert = "cert.pem"
+	c.Store = "postgres"
+	c.LogFormat = "xml"
+	c.LogLevel = "loud"
+	c.ShutdownDelay = Duration(-time.Second)
+	c.MaxHeaderBytes = 10
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate = nil, want errors")
+	}
+	for _, want := range []string{
+		"addr: required",
+		"tls-cert and tls-key must be set together",
+		`store: "postgres" is not one of memory, sqlite`,
+		`log-format: "xml" is not one of json, text`,
+		`log-level: "loud" is not one of`,
+		"shutdown-delay: must not be negative",
+		"max-header-bytes: 10 is below",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate error lacks %q:\n%v", want, err)
+		}
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 7 {
+		t.Errorf("Validate joined %d errors, want 7", n)
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default().Validate() = %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Default()
+	c.AdminToken = "hunter2"
+
+	if r := c.Redacted(); r.AdminToken != "REDACTED" || r.Addr != c.Addr {
+		t.Errorf("Redacted = %+v, want only the token hidden", r)
+	}
+	if c.AdminToken != "hunter2" {
+		t.Error("Redacted changed the original")
+	}
+	if r := Default().Redacted(); r.AdminToken != "" {
+		t.Errorf("Redacted without a token = %q, want it left empty", r.AdminToken)
+	}
+
+	var b strings.Builder
+	slog.New(slog.NewTextHandler(&b, nil)).Info("config", "config", c)
+	if out := b.String(); strings.Contains(out, "hunter2") || !strings.Contains(out, "config.admin-token=REDACTED") ||
+		!strings.Contains(out, "config.addr=:8080") || !strings.Contains(out, "config.shutdown-delay=5s") {
+		t.Errorf("logged config = %s", out)
+	}
+}
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// LoadError points at the catalog entry that failed to load.
+type LoadError struct {
+	File string
+	Line int
+	Col  int
+	ID   string // empty when the entry could not be decoded far enough
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	loc := fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Col)
+	if e.ID != "" {
+		return fmt.Sprintf("%s: plant %q: %v", loc, e.ID, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", loc, e.Err)
+}
+
+func (e *LoadError) Unwrap() error { return e.Err }
+
+// Load reads the plant catalog from path. A directory is read as a set of
+// *.json files in name order; a single file is read on its own. Each file
+// holds either one plant object or an array of them (a bundle).
+//
+// Every entry is decoded strictly and validated. All problems are collected
+// and returned together as *LoadError values joined with errors.Join.
+func Load(path string) ([]models.Plant, error) {
+	plants, _, err := load(path)
+	return plants, err
+}
+
+// load is Load plus a version string: a short hash over the names and
+// contents of every catalog file.
+func load(path string) ([]models.Plant, string, error) {
+	files, err := catalogFiles(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		plants []models.Plant
+		errs   []error
+		seen   = map[string]string{} // id → location of first definition
+		hash   = sha256.New()
+	)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.Base(file), len(src))
+		hash.Write(src)
+		entries, err := decodeFile(file, src)
+		errs = append(errs, err)
+		for _, e := range entries {
+			if first, dup := seen[e.plant.ID]; dup {
+				errs = append(errs, e.fail(fmt.Errorf("duplicate id, first defined at %s", first)))
+				continue
+			}
+			seen[e.plant.ID] = fmt.Sprintf("%s:%d", e.file, e.line)
+			plants = append(plants, e.plant)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, "", err
+	}
+	if len(plants) == 0 {
+		return nil, "", fmt.Errorf("catalog %s: no plants found", path)
+	}
+	return plants, hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+func catalogFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	slices.Sort(files)
+	return files, err
+}
+
+type entry struct {
+	file  string
+	line  int
+	col   int
+	plant models.Plant
+}
+
+func (e entry) fail(err error) *LoadError {
+	return &LoadError{File: e.file, Line: e.line, Col: e.col, ID: e.plant.ID, Err: err}
+}
+
+// decodeFile splits src into plant entries and decodes each one, keeping
+// the offset of every entry so errors can point at the right line.
+func decodeFile(file string, src []byte) ([]entry, error) {
+	at := func(off int64, err error) *LoadError {
+		line, col := position(src, off)
+		return &LoadError{File: file, Line: line, Col: col, Err: err}
+	}
+
+	start := skipSpace(src, 0)
+	if start == len(src) {
+		return nil, at(0, errors.New("empty file"))
+	}
+
+	var raws []json.RawMessage
+	var offsets []int64
+	dec := json.NewDecoder(bytes.NewReader(src))
+	if src[start] == '[' {
+		if _, err := dec.Token(); err != nil {
+			return nil, at(dec.InputOffset(), err)
+		}
+		for dec.More() {
+			off := int64(skipSpace(src, int(dec.InputOffset())))
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, at(errOffset(err, off), err)
+			}
+			raws, offsets = append(raws, raw), append(offsets, off)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, at(dec.InputOffset(), err)
+		}
+	} else {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, at(errOffset(err, int64(start)), err)
+		}
+		raws, offsets = append(raws, raw), append(offsets, int64(start))
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, at(dec.InputOffset(), errors.New("unexpected data after catalog entries"))
+	}
+
+	var (
+		out  []entry
+		errs []error
+	)
+	for i, raw := range raws {
+		line, col := position(src, offsets[i])
+		e := entry{file: file, line: line, col: col}
+
+		strict := json.NewDecoder(bytes.NewReader(raw))
+		strict.DisallowUnknownFields()
+		if err := strict.Decode(&e.plant); err != nil {
+			le := at(offsets[i]+errOffset(err, 0), err)
+			le.ID = e.plant.ID
+			errs = append(errs, le)
+			continue
+		}
+		if err := e.plant.Validate(); err != nil {
+			for _, v := range flatten(err) {
+				errs = append(errs, e.fail(v))
+			}
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, errors.Join(errs...)
+}
+
+// flatten unpacks an errors.Join result so each problem gets its own LoadError.
+func flatten(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
+
+// errOffset extracts the byte offset carried by encoding/json errors,
+// falling back to def when the error has none.
+func errOffset(err error, def int64) int64 {
+	var syn *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syn):
+		return syn.Offset
+	case errors.As(err, &typ):
+		return typ.Offset
+	}
+	return def
+}
+
+func skipSpace(src []byte, i int) int {
+	for i < len(src) {
+		switch src[i] {
+		case ' ', '\t', '\r', '\n', ',':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(src []byte, off int64) (line, col int) {
+	off = min(max(off, 0), int64(len(src)))
+	before := src[:off]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = int(off) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// plantJSON is a valid catalog entry on a single line; extra fields are
+// spliced in, overriding the defaults.
+func plantJSON(id string, extra ...string) string {
+	fields := append([]string{
+		`"id": "` + id + `"`, `"name": "` + id + `"`, `"image": "/static/` + id + `.jpg"`,
+		`"lightCondition": ["low-light"]`, `"careLevel": "low"`, `"plantType": "foliage"`,
+		`"location": "indoor"`, `"size": "small"`,
+	}, extra...)
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// writeCatalog writes files (name → content) to a fresh directory.
+func writeCatalog(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// loadErrors unpacks every *LoadError from a Load error, which joins
+// the errors of each file.
+func loadErrors(t *testing.T, err error) []*LoadError {
+	t.Helper()
+	if le, ok := err.(*LoadError); ok {
+		return []*LoadError{le}
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("%v is not a *LoadError", err)
+	}
+	var out []*LoadError
+	for _, e := range joined.Unwrap() {
+		out = append(out, loadErrors(t, e)...)
+	}
+	return out
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeCatalog(t, map[string]string{
+		"b.json":    "[\n  " + plantJSON("ivy") + ",\n  " + plantJSON("fern") + "\n]\n",
+		"a.json":    plantJSON("moss") + "\n",
+		"notes.txt": "not a catalog file",
+	})
+	plants, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, p := range plants {
+		ids = append(ids, p.ID)
+	}
+	if want := []string{"moss", "ivy", "fern"}; !slices.Equal(ids, want) {
+		t.Errorf("plants = %v, want files in name order, entries in file order: %v", ids, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	bundle := strings.Join([]string{
+		"[",
+		"  " + plantJSON("fern") + ",",
+		"  " + plantJSON("ivy", `"careLevel": "banana"`, `"size": "huge"`) + ",",
+		"  " + plantJSON("moss", `"colour": "green"`) + ",",
+		"  " + plantJSON("palm"),
+		"]",
+	}, "\n")
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []LoadError // Err is matched by substring of its message
+	}{
+		{"invalid entries in a bundle", map[string]string{"bundle.json": bundle}, []LoadError{
+			{File: "bundle.json", Line: 3, Col: 3, ID: "ivy", Err: errors.New(`careLevel: "banana" is not one of`)},
+			{File: "bundle.json", Line: 3, Col: 3, ID: "ivy", Err: errors.New(`size: "huge" is not one of`)},
+			{File: "bundle.json", Line: 4, Col: 3, ID: "moss", Err: errors.New(`unknown field "colour"`)},
+		}},
+		{"duplicate id across files", map[string]string{
+			"a.json": plantJSON("fern"),
+			"b.json": "[\n  " + plantJSON("ivy") + ",\n  " + plantJSON("fern") + "\n]",
+		}, []LoadError{
+			{File: "b.json", Line: 3, Col: 3, ID: "fern", Err: errors.New("duplicate id, first defined at")},
+		}},
+		{"duplicate id in one bundle", map[string]string{
+			"a.json": "[\n" + plantJSON("fern") + ",\n\n" + plantJSON("fern") + "\n]",
+		}, []LoadError{
+			{File: "a.json", Line: 4, Col: 1, ID: "fern", Err: errors.New("a.json:2")},
+		}},
+		{"syntax error", map[string]string{
+			"a.json": "[\n  " + plantJSON("fern") + ",\n  {\"id\": \"ivy\",,}\n]",
+		}, []LoadError{
+			{File: "a.json", Line: 3, ID: "", Err: errors.New("invalid character")},
+		}},
+		{"empty file", map[string]string{"a.json": "\n"}, []LoadError{
+			{File: "a.json", Line: 1, Col: 1, Err: errors.New("empty file")},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeCatalog(t, tc.files)
+			plants, err := Load(dir)
+			if err == nil {
+				t.Fatalf("Load = %d plants, want an error", len(plants))
+			}
+			got := loadErrors(t, err)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(got), len(tc.want), err)
+			}
+			for i, want := range tc.want {
+				g := got[i]
+				if filepath.Base(g.File) != want.File || g.Line != want.Line || g.ID != want.ID ||
+					(want.Col != 0 && g.Col != want.Col) || !strings.Contains(g.Err.Error(), want.Err.Error()) {
+					t.Errorf("error %d = %s:%d:%d id %q: %v\nwant %s:%d:%d id %q: %v", i,
+						filepath.Base(g.File), g.Line, g.Col, g.ID, g.Err, want.File, want.Line, want.Col, want.ID, want.Err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// MemoryRepository serves plants straight from a catalog Store,
+// scanning the current snapshot on every query.
+//
+// Edits made through Create, Update and Retire are kept in an overlay on
+// top of the snapshot, survive catalog reloads and take precedence over
+// the files, but are lost on restart.
+type MemoryRepository struct {
+	store *Store
+
+	mu      sync.RWMutex
+	overlay map[string]models.Plant
+	edits   int
+}
+
+var _ PlantRepository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository returns a repository over store's snapshots,
+// so catalog hot reloads are visible immediately.
+func NewMemoryRepository(store *Store) *MemoryRepository {
+	return &MemoryRepository{store: store, overlay: map[string]models.Plant{}}
+}
+
+// plants merges the current snapshot with the edit overlay.
+func (m *MemoryRepository) plants() []models.Plant {
+	snap := m.store.Snapshot().Plants
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.overlay) == 0 {
+		return snap
+	}
+
+	out := make([]models.Plant, 0, len(snap)+len(m.overlay))
+	seen := make(map[string]bool, len(snap))
+	for _, p := range snap {
+		seen[p.ID] = true
+		if e, ok := m.overlay[p.ID]; ok {
+			p = e
+		}
+		out = append(out, p)
+	}
+	for id, p := range m.overlay {
+		if !seen[id] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (m *MemoryRepository) Get(_ context.Context, id string) (models.Plant, error) {
+	for _, p := range m.plants() {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return models.Plant{}, ErrNotFound
+}
+
+func (m *MemoryRepository) List(ctx context.Context, offset, limit int) ([]models.Plant, int, error) {
+	all, err := m.Find(ctx, PlantFilter{})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page(all, offset, limit), len(all), nil
+}
+
+// page returns the [offset, offset+limit) window of plants.
+func page(plants []models.Plant, offset, limit int) []models.Plant {
+	offset = min(max(offset, 0), len(plants))
+	end := len(plants)
+	if limit > 0 {
+		end = min(offset+limit, end)
+	}
+	return plants[offset:end]
+}
+
+func (m *MemoryRepository) Find(_ context.Context, f PlantFilter) ([]models.Plant, error) {
+	var out []models.Plant
+	for _, p := range m.plants() {
+		if !p.Retired() && f.Match(p) {
+			out = append(out, p)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.Plant) int { return cmp.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+func (m *MemoryRepository) Info(context.Context) (CatalogInfo, error) {
+	snap := m.store.Snapshot()
+	plants := 0
+	for _, p := range m.plants() {
+		if !p.Retired() {
+			plants++
+		}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return CatalogInfo{Version: editedVersion(snap.Version, m.edits), Plants: plants, LoadedAt: snap.LoadedAt}, nil
+}
+
+func (m *MemoryRepository) Create(_ context.Context, p models.Plant) error {
+	return m.edit(p.ID, func(_ models.Plant, exists bool) (models.Plant, error) {
+		if exists {
+			return models.Plant{}, ErrExists
+		}
+		return p, nil
+	})
+}
+
+func (m *MemoryRepository) Update(_ context.Context, p models.Plant) error {
+	return m.edit(p.ID, func(_ models.Plant, exists bool) (models.Plant, error) {
+		if !exists {
+			return models.Plant{}, ErrNotFound
+		}
+		return p, nil
+	})
+}
+
+func (m *MemoryRepository) Retire(_ context.Context, id string, at time.Time) error {
+	return m.edit(id, func(cur models.Plant, exists bool) (models.Plant, error) {
+		if !exists {
+			return models.Plant{}, ErrNotFound
+		}
+		if !cur.Retired() {
+			at := at.UTC()
+			cur.RetiredAt = &at
+		}
+		return cur, nil
+	})
+}
+
+// edit applies fn to the current version of plant id under the write lock
+// and stores the result in the overlay.
+func (m *MemoryRepository) edit(id string, fn func(cur models.Plant, exists bool) (models.Plant, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cur, exists := m.overlay[id]
+	if !exists {
+		for _, p := range m.store.Snapshot().Plants {
+			if p.ID == id {
+				cur, exists = p, true
+				break
+			}
+		}
+	}
+	next, err := fn(cur, exists)
+	if err != nil {
+		return err
+	}
+	m.overlay[id] = next
+	m.edits++
+	return nil
+}
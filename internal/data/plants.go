@@ -1,273 +0,0 @@
-package data
-
-import "github.com/example/leaf-love-go/internal/models"
-
-var Plants = []models.Plant{
-	{
-		ID:             "rose-bush",
-		Name:           "Rose Bush",
-		ScientificName: "Rosa spp.",
-		Description:    "Classic flowering shrub with fragrant blooms, ideal for sunny gardens.",
-		Image:          "/static/rose-bush.jpg",
-		LightCondition: []string{"full-sun"},
-		CareLevel:      "high",
-		PlantType:      "flowering",
-		Location:       "outdoor",
-		Size:           "large",
-		Features:       []string{"Fragrant", "Colorful blooms"},
-		Care: models.CareInstructions{
-			Watering:    "Water deeply weekly; more often in hot weather",
-			Light:       "Full sun",
-			Temperature: "15-27°C (60-80°F)",
-			Humidity:    "Average",
-		},
-	},
-	{
-		ID:             "lavender",
-		Name:           "Lavender",
-		ScientificName: "Lavandula",
-		Description:    "Fragrant herb with purple flowers, great for outdoor beds and pots.",
-		Image:          "/static/lavender.jpg",
-		LightCondition: []string{"full-sun"},
-		CareLevel:      "low",
-		PlantType:      "flowering",
-		Location:       "both",
-		Size:           "small",
-		Features:       []string{"Drought tolerant", "Fragrant"},
-		Care: models.CareInstructions{
-			Watering:    "Water sparingly once established",
-			Light:       "Full sun",
-			Temperature: "10-30°C (50-85°F)",
-			Humidity:    "Low",
-		},
-	},
-	{
-		ID:             "orchid",
-		Name:           "Phalaenopsis Orchid",
-		ScientificName: "Phalaenopsis",
-		Description:    "Elegant indoor flowering plant with long-lasting blooms.",
-		Image:          "/static/orchid.jpg",
-		LightCondition: []string{"partial-shade"},
-		CareLevel:      "high",
-		PlantType:      "flowering",
-		Location:       "indoor",
-		Size:           "small",
-		Features:       []string{"Long-lasting flowers", "Elegant appearance"},
-		Care: models.CareInstructions{
-			Watering:    "Water weekly; avoid crown rot",
-			Light:       "Bright, indirect light",
-			Temperature: "18-24°C (65-75°F)",
-			Humidity:    "High",
-		},
-	},
-	{
-		ID:             "aloe-vera",
-		Name:           "Aloe Vera",
-		ScientificName: "Aloe barbadensis miller",
-		Description:    "Succulent with medicinal gel, easy to grow indoors or outdoors.",
-		Image:          "/static/aloe-vera.jpg",
-		LightCondition: []string{"full-sun", "partial-shade"},
-		CareLevel:      "low",
-		PlantType:      "succulent",
-		Location:       "both",
-		Size:           "medium",
-		Features:       []string{"Medicinal uses", "Drought tolerant"},
-		Care: models.CareInstructions{
-			Watering:    "Water deeply but infrequently",
-			Light:       "Bright light, some direct sun",
-			Temperature: "15-29°C (60-85°F)",
-			Humidity:    "Low",
-		},
-	},
-	{
-		ID:             "bonsai",
-		Name:           "Bonsai Tree",
-		ScientificName: "Various species",
-		Description:    "Miniature tree grown in containers, requires precise care.",
-		Image:          "/static/bonsai.jpg",
-		LightCondition: []string{"partial-shade"},
-		CareLevel:      "high",
-		PlantType:      "foliage",
-		Location:       "indoor",
-		Size:           "small",
-		Features:       []string{"Decorative", "Artistic form"},
-		Care: models.CareInstructions{
-			Watering:    "Keep soil consistently moist, not soggy",
-			Light:       "Bright, indirect light",
-			Temperature: "15-25°C (60-77°F)",
-			Humidity:    "Average to high",
-		},
-	},
-	{
-		ID:             "sunflower",
-		Name:           "Sunflower",
-		ScientificName: "Helianthus annuus",
-		Description:    "Tall, vibrant flowers that track the sun; great for outdoor gardens.",
-		Image:          "/static/sunflower.jpg",
-		LightCondition: []string{"full-sun"},
-		CareLevel:      "medium",
-		PlantType:      "flowering",
-		Location:       "outdoor",
-		Size:           "large",
-		Features:       []string{"Attracts pollinators", "Fast growing"},
-		Care: models.CareInstructions{
-			Watering:    "Water regularly, especially during dry periods",
-			Light:       "Full sun",
-			Temperature: "18-30°C (65-86°F)",
-			Humidity:    "Average",
-		},
-	},
-	{
-		ID:             "jade-plant",
-		Name:           "Jade Plant",
-		ScientificName: "Crassula ovata",
-		Description:    "Long-lived succulent with thick, shiny leaves; symbol of good luck.",
-		Image:          "/static/jade-plant.jpg",
-		LightCondition: []string{"full-sun", "partial-shade"},
-		CareLevel:      "medium",
-		PlantType:      "succulent",
-		Location:       "indoor",
-		Size:           "medium",
-		Features:       []string{"Low maintenance", "Long-lived"},
-		Care: models.CareInstructions{
-			Watering:    "Allow soil to dry between waterings",
-			Light:       "Bright light",
-			Temperature: "18-24°C (65-75°F)",
-			Humidity:    "Low",
-		},
-	},
-	{
-		ID:             "hibiscus",
-		Name:           "Tropical Hibiscus",
-		ScientificName: "Hibiscus rosa-sinensis",
-		Description:    "Bright, showy flowers; thrives in warm outdoor climates.",
-		Image:          "/static/hibiscus.jpg",
-		LightCondition: []string{"full-sun", "partial-shade"},
-		CareLevel:      "high",
-		PlantType:      "flowering",
-		Location:       "both",
-		Size:           "medium",
-		Features:       []string{"Large colorful blooms", "Attracts hummingbirds"},
-		Care: models.CareInstructions{
-			Watering:    "Keep soil evenly moist",
-			Light:       "Full sun to partial shade",
-			Temperature: "18-32°C (65-90°F)",
-			Humidity:    "High",
-		},
-	},
-
-	{
-		ID:             "monstera",
-		Name:           "Monstera Deliciosa",
-		ScientificName: "Monstera deliciosa",
-		Description:    "Iconic Swiss cheese plant with perforated leaves; tropical vibe.",
-		Image:          "/static/monstera.jpg",
-		LightCondition: []string{"partial-shade", "low-light"},
-		CareLevel:      "medium",
-		PlantType:      "foliage",
-		Location:       "indoor",
-		Size:           "large",
-		Features:       []string{"Statement plant", "Fast growing", "Air-purifying"},
-		Care: models.CareInstructions{
-			Watering:    "Water when top inch of soil is dry",
-			Light:       "Bright, indirect to medium light",
-			Temperature: "18-27°C (65-80°F)",
-			Humidity:    "Average to high",
-		},
-	},
-	{
-		ID:             "pothos",
-		Name:           "Pothos",
-		ScientificName: "Epipremnum aureum",
-		Description:    "Low-maintenance trailing vine that thrives in many conditions.",
-		Image:          "/static/pothos.jpg",
-		LightCondition: []string{"low-light", "partial-shade"},
-		CareLevel:      "low",
-		PlantType:      "foliage",
-		Location:       "indoor",
-		Size:           "medium",
-		Features:       []string{"Very easy care", "Trailing", "Air-purifying"},
-		Care: models.CareInstructions{
-			Watering:    "Water when soil is dry; forgiving",
-			Light:       "Low to bright indirect light",
-			Temperature: "18-29°C (65-85°F)",
-			Humidity:    "Average home humidity",
-		},
-	},
-	{
-		ID:             "succulents",
-		Name:           "Succulent Mix",
-		ScientificName: "Various species",
-		Description:    "Water-storing plants with geometric beauty and minimal care.",
-		Image:          "/static/succulents.jpg",
-		LightCondition: []string{"full-sun", "partial-shade"},
-		CareLevel:      "low",
-		PlantType:      "succulent",
-		Location:       "indoor",
-		Size:           "small",
-		Features:       []string{"Drought tolerant", "Great for desks", "Low care"},
-		Care: models.CareInstructions{
-			Watering:    "Infrequent; let soil dry completely",
-			Light:       "Bright light, some direct sun",
-			Temperature: "18-29°C (65-85°F)",
-			Humidity:    "Low humidity fine",
-		},
-	},
-	{
-		ID:             "snake-plant",
-		Name:           "Snake Plant",
-		ScientificName: "Sansevieria trifasciata",
-		Description:    "Architectural plant tolerant of neglect and low light.",
-		Image:          "/static/snake-plant.jpg",
-		LightCondition: []string{"low-light", "partial-shade", "full-sun"},
-		CareLevel:      "low",
-		PlantType:      "foliage",
-		Location:       "indoor",
-		Size:           "medium",
-		Features:       []string{"Tolerates low light", "Drought tolerant", "Air-purifying"},
-		Care: models.CareInstructions{
-			Watering:    "Water sparingly; avoid overwatering",
-			Light:       "Low to bright light",
-			Temperature: "15-29°C (60-85°F)",
-			Humidity:    "Low to average",
-		},
-	},
-	{
-		ID:             "peace-lily",
-		Name:           "Peace Lily",
-		ScientificName: "Spathiphyllum",
-		Description:    "Elegant foliage and white blooms; enjoys consistent moisture.",
-		Image:          "/static/peace-lily.jpg",
-		LightCondition: []string{"low-light", "partial-shade"},
-		CareLevel:      "medium",
-		PlantType:      "flowering",
-		Location:       "indoor",
-		Size:           "medium",
-		Features:       []string{"Blooms indoors", "Air-purifying"},
-		Care: models.CareInstructions{
-			Watering:    "Keep soil slightly moist; droops when thirsty",
-			Light:       "Low to medium indirect light",
-			Temperature: "18-27°C (65-80°F)",
-			Humidity:    "Average to high",
-		},
-	},
-	{
-		ID:             "rubber-tree",
-		Name:           "Rubber Tree",
-		ScientificName: "Ficus elastica",
-		Description:    "Glossy, dramatic leaves; fast-growing statement plant.",
-		Image:          "/static/rubber-tree.jpg",
-		LightCondition: []string{"partial-shade"},
-		CareLevel:      "medium",
-		PlantType:      "foliage",
-		Location:       "indoor",
-		Size:           "large",
-		Features:       []string{"Glossy leaves", "Statement plant", "Fast growing"},
-		Care: models.CareInstructions{
-			Watering:    "Water when top inch is dry",
-			Light:       "Bright, indirect light",
-			Temperature: "18-24°C (65-75°F)",
-			Humidity:    "Average to high",
-		},
-	},
-}
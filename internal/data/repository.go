@@ -0,0 +1,84 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+var (
+	// ErrNotFound is returned when a plant ID does not exist.
+	ErrNotFound = errors.New("plant not found")
+	// ErrExists is returned when creating a plant whose ID is taken,
+	// including by a retired plant.
+	ErrExists = errors.New("plant id already exists")
+)
+
+// PlantFilter narrows a plant query. An empty field matches every plant;
+// a field with several values matches plants with any of them.
+type PlantFilter struct {
+	LightConditions []string
+	CareLevels      []string
+	PlantTypes      []string
+	Locations       []string
+	Sizes           []string
+}
+
+// Match reports whether p passes the filter.
+func (f PlantFilter) Match(p models.Plant) bool {
+	anyOf := func(want []string, have ...string) bool {
+		if len(want) == 0 {
+			return true
+		}
+		for _, h := range have {
+			if slices.Contains(want, h) {
+				return true
+			}
+		}
+		return false
+	}
+	return anyOf(f.LightConditions, p.LightCondition...) &&
+		anyOf(f.CareLevels, p.CareLevel) &&
+		anyOf(f.PlantTypes, p.PlantType) &&
+		anyOf(f.Locations, p.Location) &&
+		anyOf(f.Sizes, p.Size)
+}
+
+// CatalogInfo describes the catalog a repository is serving.
+type CatalogInfo struct {
+	Version  string
+	Plants   int
+	LoadedAt time.Time
+}
+
+// PlantRepository is the storage behind the recommender and the API.
+// List and Find skip retired plants and order results by name; Get
+// returns retired plants too so their history stays reachable.
+type PlantRepository interface {
+	Get(ctx context.Context, id string) (models.Plant, error)
+	// List returns one page of plants and the total across all pages.
+	// A limit of zero or less means no limit.
+	List(ctx context.Context, offset, limit int) (plants []models.Plant, total int, err error)
+	Find(ctx context.Context, f PlantFilter) ([]models.Plant, error)
+	Info(ctx context.Context) (CatalogInfo, error)
+
+	// Create adds a new plant; ErrExists if the ID is taken.
+	Create(ctx context.Context, p models.Plant) error
+	// Update replaces an existing plant; ErrNotFound if there is none.
+	Update(ctx context.Context, p models.Plant) error
+	// Retire soft-deletes a plant. Retiring a retired plant is a no-op.
+	Retire(ctx context.Context, id string, at time.Time) error
+}
+
+// editedVersion derives the catalog version after edits made through
+// the repository on top of a loaded or imported catalog.
+func editedVersion(base string, edits int) string {
+	if edits == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s+%d", base, edits)
+}
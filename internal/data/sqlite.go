@@ -0,0 +1,392 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// SQLiteDriver is the database/sql driver name used by OpenSQLite.
+// No driver is linked by default; build with -tags sqlite to link
+// modernc.org/sqlite (see sqlite_driver.go).
+const SQLiteDriver = "sqlite"
+
+// migrations are applied in order; a migration's version is its index + 1.
+// Never edit an applied migration, append a new one instead.
+var migrations = []string{
+	// 1: plants, their light conditions and lookup indexes.
+	`CREATE TABLE plants (
+		id              TEXT PRIMARY KEY,
+		name            TEXT NOT NULL,
+		scientific_name TEXT NOT NULL DEFAULT '',
+		description     TEXT NOT NULL DEFAULT '',
+		image           TEXT NOT NULL DEFAULT '',
+		care_level      TEXT NOT NULL,
+		plant_type      TEXT NOT NULL,
+		location        TEXT NOT NULL,
+		size            TEXT NOT NULL,
+		features        TEXT NOT NULL DEFAULT '[]',
+		care            TEXT NOT NULL DEFAULT '{}'
+	);
+	CREATE INDEX plants_name       ON plants(name);
+	CREATE INDEX plants_care_level ON plants(care_level);
+	CREATE INDEX plants_plant_type ON plants(plant_type);
+	CREATE INDEX plants_location   ON plants(location);
+	CREATE INDEX plants_size       ON plants(size);
+	CREATE TABLE plant_light (
+		plant_id TEXT NOT NULL REFERENCES plants(id),
+		light    TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		PRIMARY KEY (plant_id, light)
+	);
+	CREATE INDEX plant_light_light ON plant_light(light, plant_id);
+	CREATE TABLE meta (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`,
+	// 2: soft delete.
+	`ALTER TABLE plants ADD COLUMN retired_at TEXT;
+	CREATE INDEX plants_retired_at ON plants(retired_at);`,
+	// 3: alias names, botanical synonyms and taxonomy.
+	`ALTER TABLE plants ADD COLUMN aliases TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE plants ADD COLUMN synonyms TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE plants ADD COLUMN taxonomy TEXT NOT NULL DEFAULT '{}';`,
+}
+
+// SQLiteRepository stores plants in an embedded SQLite database.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+var _ PlantRepository = (*SQLiteRepository)(nil)
+
+// OpenSQLite opens (creating if needed) the database at path and brings
+// its schema up to date.
+func OpenSQLite(ctx context.Context, path string) (*SQLiteRepository, error) {
+	db, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %s: %w (was the binary built with -tags sqlite?)", path, err)
+	}
+	// SQLite allows one writer at a time; a single connection avoids
+	// "database is locked" errors without extra tuning.
+	db.SetMaxOpenConns(1)
+	repo := &SQLiteRepository{db: db}
+	if err := repo.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close releases the database.
+func (r *SQLiteRepository) Close() error { return r.db.Close() }
+
+func (r *SQLiteRepository) migrate(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	var current int
+	if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	for i := current; i < len(migrations); i++ {
+		err := r.tx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+				i+1, time.Now().UTC().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Import upserts plants and records version as the catalog version.
+// Plants already in the database but not in plants are left alone, and
+// retired plants stay retired.
+func (r *SQLiteRepository) Import(ctx context.Context, plants []models.Plant, version string) error {
+	return r.tx(ctx, func(tx *sql.Tx) error {
+		for _, p := range plants {
+			if err := writePlant(ctx, tx, upsertPlant, p); err != nil {
+				return fmt.Errorf("import %s: %w", p.ID, err)
+			}
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES ('version', ?), ('edits', '0'), ('updated_at', ?)
+			ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+			version, time.Now().UTC().Format(time.RFC3339Nano))
+		return err
+	})
+}
+
+func (r *SQLiteRepository) Create(ctx context.Context, p models.Plant) error {
+	return r.tx(ctx, func(tx *sql.Tx) error {
+		var n int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM plants WHERE id = ?`, p.ID).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrExists
+		}
+		if err := writePlant(ctx, tx, insertPlant, p); err != nil {
+			return err
+		}
+		return recordEdit(ctx, tx)
+	})
+}
+
+func (r *SQLiteRepository) Update(ctx context.Context, p models.Plant) error {
+	return r.tx(ctx, func(tx *sql.Tx) error {
+		if err := writePlant(ctx, tx, updatePlant, p); err != nil {
+			return err
+		}
+		return recordEdit(ctx, tx)
+	})
+}
+
+func (r *SQLiteRepository) Retire(ctx context.Context, id string, at time.Time) error {
+	return r.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE plants SET retired_at = COALESCE(retired_at, ?) WHERE id = ?`,
+			at.UTC().Format(time.RFC3339Nano), id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrNotFound
+		}
+		return recordEdit(ctx, tx)
+	})
+}
+
+// Plant write statements share one positional argument order, see writePlant.
+const (
+	insertPlant = `INSERT INTO plants
+		(id, name, scientific_name, description, image, care_level, plant_type, location, size, features, care, retired_at,
+		 aliases, synonyms, taxonomy)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15)`
+	upsertPlant = insertPlant + `
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name, scientific_name = excluded.scientific_name,
+			description = excluded.description, image = excluded.image,
+			care_level = excluded.care_level, plant_type = excluded.plant_type,
+			location = excluded.location, size = excluded.size,
+			features = excluded.features, care = excluded.care,
+			aliases = excluded.aliases, synonyms = excluded.synonyms, taxonomy = excluded.taxonomy,
+			retired_at = COALESCE(excluded.retired_at, plants.retired_at)`
+	updatePlant = `UPDATE plants SET
+		name = ?2, scientific_name = ?3, description = ?4, image = ?5,
+		care_level = ?6, plant_type = ?7, location = ?8, size = ?9,
+		features = ?10, care = ?11, retired_at = ?12,
+		aliases = ?13, synonyms = ?14, taxonomy = ?15
+		WHERE id = ?1`
+)
+
+// writePlant runs one of the plant write statements and replaces the
+// plant's light conditions. It returns ErrNotFound if no row was written.
+func writePlant(ctx context.Context, tx *sql.Tx, stmt string, p models.Plant) error {
+	var cols [5]string // features, care, aliases, synonyms, taxonomy as JSON
+	for i, v := range []any{p.Features, p.Care, nonNil(p.Aliases), nonNil(p.Synonyms), p.Taxonomy} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		cols[i] = string(b)
+	}
+	var retired sql.NullString
+	if p.RetiredAt != nil {
+		retired = sql.NullString{String: p.RetiredAt.UTC().Format(time.RFC3339Nano), Valid: true}
+	}
+	res, err := tx.ExecContext(ctx, stmt,
+		p.ID, p.Name, p.ScientificName, p.Description, p.Image,
+		p.CareLevel, p.PlantType, p.Location, p.Size, cols[0], cols[1], retired,
+		cols[2], cols[3], cols[4])
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM plant_light WHERE plant_id = ?`, p.ID); err != nil {
+		return err
+	}
+	for i, l := range p.LightCondition {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO plant_light (plant_id, light, position) VALUES (?, ?, ?)`, p.ID, l, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nonNil stores absent name lists as [] rather than null.
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+func recordEdit(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES ('edits', '1'), ('updated_at', ?)
+		ON CONFLICT (key) DO UPDATE SET value = CASE key
+			WHEN 'edits' THEN CAST(CAST(meta.value AS INTEGER) + 1 AS TEXT)
+			ELSE excluded.value END`,
+		time.Now().UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+const selectPlants = `SELECT p.id, p.name, p.scientific_name, p.description, p.image,
+	p.care_level, p.plant_type, p.location, p.size, p.features, p.care, p.retired_at,
+	p.aliases, p.synonyms, p.taxonomy,
+	COALESCE((SELECT group_concat(light, ',') FROM
+		(SELECT light FROM plant_light WHERE plant_id = p.id ORDER BY position)), '')
+	FROM plants p`
+
+func (r *SQLiteRepository) Get(ctx context.Context, id string) (models.Plant, error) {
+	plants, err := r.query(ctx, selectPlants+` WHERE p.id = ?`, id)
+	if err != nil {
+		return models.Plant{}, err
+	}
+	if len(plants) == 0 {
+		return models.Plant{}, ErrNotFound
+	}
+	return plants[0], nil
+}
+
+func (r *SQLiteRepository) List(ctx context.Context, offset, limit int) ([]models.Plant, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM plants WHERE retired_at IS NULL`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	plants, err := r.query(ctx, selectPlants+` WHERE p.retired_at IS NULL ORDER BY p.name LIMIT ? OFFSET ?`, limit, max(offset, 0))
+	return plants, total, err
+}
+
+// Find turns each non-empty filter field into an indexed IN clause.
+func (r *SQLiteRepository) Find(ctx context.Context, f PlantFilter) ([]models.Plant, error) {
+	var (
+		where = []string{`p.retired_at IS NULL`}
+		args  []any
+	)
+	in := func(expr string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		where = append(where, fmt.Sprintf(expr, strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")))
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	in(`p.id IN (SELECT plant_id FROM plant_light WHERE light IN (%s))`, f.LightConditions)
+	in(`p.care_level IN (%s)`, f.CareLevels)
+	in(`p.plant_type IN (%s)`, f.PlantTypes)
+	in(`p.location IN (%s)`, f.Locations)
+	in(`p.size IN (%s)`, f.Sizes)
+
+	q := selectPlants + ` WHERE ` + strings.Join(where, ` AND `) + ` ORDER BY p.name`
+	return r.query(ctx, q, args...)
+}
+
+func (r *SQLiteRepository) query(ctx context.Context, q string, args ...any) ([]models.Plant, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Plant
+	for rows.Next() {
+		var (
+			p                           models.Plant
+			features, care, light       string
+			aliases, synonyms, taxonomy string
+			retired                     sql.NullString
+		)
+		if err := rows.Scan(&p.ID, &p.Name, &p.ScientificName, &p.Description, &p.Image,
+			&p.CareLevel, &p.PlantType, &p.Location, &p.Size, &features, &care, &retired,
+			&aliases, &synonyms, &taxonomy, &light); err != nil {
+			return nil, err
+		}
+		if retired.Valid {
+			t, err := time.Parse(time.RFC3339Nano, retired.String)
+			if err != nil {
+				return nil, fmt.Errorf("plant %s retired_at: %w", p.ID, err)
+			}
+			p.RetiredAt = &t
+		}
+		if err := json.Unmarshal([]byte(features), &p.Features); err != nil {
+			return nil, fmt.Errorf("plant %s features: %w", p.ID, err)
+		}
+		if err := json.Unmarshal([]byte(care), &p.Care); err != nil {
+			return nil, fmt.Errorf("plant %s care: %w", p.ID, err)
+		}
+		if err := json.Unmarshal([]byte(aliases), &p.Aliases); err != nil {
+			return nil, fmt.Errorf("plant %s aliases: %w", p.ID, err)
+		}
+		if err := json.Unmarshal([]byte(synonyms), &p.Synonyms); err != nil {
+			return nil, fmt.Errorf("plant %s synonyms: %w", p.ID, err)
+		}
+		if err := json.Unmarshal([]byte(taxonomy), &p.Taxonomy); err != nil {
+			return nil, fmt.Errorf("plant %s taxonomy: %w", p.ID, err)
+		}
+		if light != "" {
+			p.LightCondition = strings.Split(light, ",")
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLiteRepository) Info(ctx context.Context) (CatalogInfo, error) {
+	var (
+		info    CatalogInfo
+		edits   int
+		updated string
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT
+		(SELECT COUNT(*) FROM plants WHERE retired_at IS NULL),
+		COALESCE((SELECT value FROM meta WHERE key = 'version'), ''),
+		COALESCE((SELECT CAST(value AS INTEGER) FROM meta WHERE key = 'edits'), 0),
+		COALESCE((SELECT value FROM meta WHERE key = 'updated_at'), '')`).
+		Scan(&info.Plants, &info.Version, &edits, &updated)
+	if err != nil {
+		return CatalogInfo{}, err
+	}
+	info.Version = editedVersion(info.Version, edits)
+	if updated != "" {
+		if info.LoadedAt, err = time.Parse(time.RFC3339Nano, updated); err != nil {
+			return CatalogInfo{}, fmt.Errorf("meta updated_at: %w", err)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package data
+
+// Link the pure-Go SQLite driver (registered as "sqlite") into builds
+// made with -tags sqlite.
+import _ "modernc.org/sqlite"
@@ -0,0 +1,174 @@
+//go:build sqlite
+
+package data
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// openTestSQLite imports the repository's catalog into a fresh database
+// and returns it together with an in-memory repository over the same files.
+func openTestSQLite(t *testing.T) (*SQLiteRepository, *MemoryRepository) {
+	t.Helper()
+	ctx := context.Background()
+	store, err := NewStore("../../catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := OpenSQLite(ctx, filepath.Join(t.TempDir(), "plants.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	snap := store.Snapshot()
+	if err := repo.Import(ctx, snap.Plants, snap.Version); err != nil {
+		t.Fatal(err)
+	}
+	return repo, NewMemoryRepository(store)
+}
+
+func ids(plants []models.Plant) []string {
+	out := make([]string, len(plants))
+	for i, p := range plants {
+		out[i] = p.ID
+	}
+	return out
+}
+
+func schemaVersion(t *testing.T, repo *SQLiteRepository) int {
+	t.Helper()
+	var v int
+	if err := repo.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "plants.db")
+
+	repo, err := OpenSQLite(ctx, path)
+	if err != nil {
+		t.Fatalf("migrating an empty database: %v", err)
+	}
+	if v := schemaVersion(t, repo); v != len(migrations) {
+		t.Errorf("schema version = %d, want %d", v, len(migrations))
+	}
+	if err := repo.Import(ctx, []models.Plant{{ID: "fern", Name: "Fern", LightCondition: []string{"low-light"},
+		CareLevel: "low", PlantType: "foliage", Location: "indoor", Size: "small"}}, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	repo.Close()
+
+	// Reopening runs migrate again, which must apply nothing and keep the data.
+	repo, err = OpenSQLite(ctx, path)
+	if err != nil {
+		t.Fatalf("re-running migrations: %v", err)
+	}
+	defer repo.Close()
+	var applied int
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("%d migrations recorded, want %d", applied, len(migrations))
+	}
+	if _, err := repo.Get(ctx, "fern"); err != nil {
+		t.Errorf("plant lost across reopen: %v", err)
+	}
+}
+
+func TestSQLiteMatchesMemory(t *testing.T) {
+	ctx := context.Background()
+	sqlite, memory := openTestSQLite(t)
+
+	want, wantTotal, err := memory.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, gotTotal, err := sqlite.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTotal != wantTotal || !slices.Equal(ids(got), ids(want)) {
+		t.Errorf("List = %v (total %d), want %v (total %d)", ids(got), gotTotal, ids(want), wantTotal)
+	}
+
+	filters := []PlantFilter{
+		{},
+		{LightConditions: []string{"low-light"}},
+		{LightConditions: []string{"partial-shade", "low-light"}, CareLevels: []string{"low"}},
+		{PlantTypes: []string{"succulent"}, Locations: []string{"indoor", "both"}},
+		{CareLevels: []string{"high"}, Sizes: []string{"large"}},
+		{LightConditions: []string{"full-sun"}, PlantTypes: []string{"foliage"}, Sizes: []string{"small"}},
+	}
+	for _, f := range filters {
+		want, err := memory.Find(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := sqlite.Find(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(ids(got), ids(want)) {
+			t.Errorf("Find(%+v) = %v, want %v", f, ids(got), ids(want))
+		}
+	}
+
+	p, err := sqlite.Get(ctx, "snake-plant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := memory.Get(ctx, "snake-plant")
+	if p.Name != m.Name || !slices.Equal(p.LightCondition, m.LightCondition) || !slices.Equal(p.Aliases, m.Aliases) || p.Taxonomy != m.Taxonomy {
+		t.Errorf("Get(snake-plant) = %+v, want %+v", p, m)
+	}
+
+	wantInfo, _ := memory.Info(ctx)
+	gotInfo, err := sqlite.Info(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotInfo.Version != wantInfo.Version || gotInfo.Plants != wantInfo.Plants {
+		t.Errorf("Info = %+v, want version %s and %d plants", gotInfo, wantInfo.Version, wantInfo.Plants)
+	}
+}
+
+func TestSQLiteRetire(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := openTestSQLite(t)
+
+	if err := repo.Retire(ctx, "pothos", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	plants, total, err := repo.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(ids(plants), "pothos") || total != len(plants) {
+		t.Errorf("List after Retire = %v (total %d), want pothos gone", ids(plants), total)
+	}
+	found, err := repo.Find(ctx, PlantFilter{LightConditions: []string{"low-light"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(ids(found), "pothos") {
+		t.Errorf("Find after Retire = %v, want pothos gone", ids(found))
+	}
+	p, err := repo.Get(ctx, "pothos")
+	if err != nil || p.RetiredAt == nil {
+		t.Errorf("Get(pothos) = %+v, %v; want it with retiredAt", p, err)
+	}
+	if err := repo.Retire(ctx, "nope", time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Retire(nope) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,152 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// Snapshot is an immutable, validated view of the catalog.
+// Callers must not modify Plants.
+type Snapshot struct {
+	Plants   []models.Plant
+	Version  string
+	LoadedAt time.Time
+}
+
+// Store holds the current catalog snapshot and can reload it from disk.
+// Readers always see a complete snapshot; a reload that fails validation
+// leaves the previous snapshot in place.
+type Store struct {
+	path    string
+	current atomic.Pointer[Snapshot]
+
+	mu       sync.Mutex // serialises reloads
+	stamp    string     // file list + mtimes + sizes of the loaded snapshot
+	badStamp string     // same, for the last source that failed to load
+	badStat  string     // error of the last fingerprint that failed
+	failures atomic.Uint64
+	lastErr  atomic.Pointer[string]
+}
+
+// NewStore loads the catalog at path. The initial load must succeed.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	stamp, err := s.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	plants, version, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	s.stamp = stamp
+	s.current.Store(&Snapshot{Plants: plants, Version: version, LoadedAt: time.Now()})
+	return s, nil
+}
+
+// Snapshot returns the catalog currently being served.
+func (s *Store) Snapshot() *Snapshot { return s.current.Load() }
+
+// Path returns the catalog source the store reads from.
+func (s *Store) Path() string { return s.path }
+
+// Failures returns how many reloads have been rejected so far.
+func (s *Store) Failures() uint64 { return s.failures.Load() }
+
+// LastError returns the message of the most recent rejected reload,
+// or "" if the last reload attempt succeeded.
+func (s *Store) LastError() string {
+	if p := s.lastErr.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// Reload re-reads the catalog if any source file changed since the last
+// successful load. It reports whether a new snapshot was swapped in. A
+// failure is reported once: retrying sources that fail the same way again
+// returns no error and is not counted.
+func (s *Store) Reload() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stamp, err := s.fingerprint()
+	if err != nil {
+		if err.Error() == s.badStat {
+			return false, nil
+		}
+		s.badStat = err.Error()
+		return false, s.reject(err)
+	}
+	s.badStat = ""
+	if stamp == s.stamp {
+		s.lastErr.Store(nil) // back to the files being served
+		return false, nil
+	}
+	if stamp == s.badStamp {
+		return false, nil
+	}
+	plants, version, err := load(s.path)
+	if err != nil {
+		s.badStamp = stamp
+		return false, s.reject(err)
+	}
+	s.stamp = stamp
+	s.lastErr.Store(nil)
+	if version == s.Snapshot().Version {
+		return false, nil // touched but unchanged
+	}
+	s.current.Store(&Snapshot{Plants: plants, Version: version, LoadedAt: time.Now()})
+	return true, nil
+}
+
+func (s *Store) reject(err error) error {
+	s.failures.Add(1)
+	msg := err.Error()
+	s.lastErr.Store(&msg)
+	return err
+}
+
+// Watch polls the catalog source every interval until ctx is done.
+// onReload is called after every reload attempt that swapped a snapshot
+// in or failed; it may be nil.
+func (s *Store) Watch(ctx context.Context, interval time.Duration, onReload func(*Snapshot, error)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			swapped, err := s.Reload()
+			if onReload != nil && (swapped || err != nil) {
+				onReload(s.Snapshot(), err)
+			}
+		}
+	}
+}
+
+// fingerprint summarises the catalog files cheaply (names, sizes, mtimes)
+// so unchanged sources can be skipped without parsing them.
+func (s *Store) fingerprint() (string, error) {
+	files, err := catalogFiles(s.path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d\n", f, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreReload(t *testing.T) {
+	dir := writeCatalog(t, map[string]string{"plants.json": plantJSON("fern")})
+	file := filepath.Join(dir, "plants.json")
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := store.Snapshot()
+
+	if swapped, err := store.Reload(); swapped || err != nil {
+		t.Errorf("Reload of unchanged files = %v, %v; want false, nil", swapped, err)
+	}
+
+	// A broken edit is rejected and the previous snapshot kept.
+	if err := os.WriteFile(file, []byte(plantJSON("fern", `"careLevel": "banana"`)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	swapped, err := store.Reload()
+	if swapped || err == nil {
+		t.Fatalf("Reload of an invalid catalog = %v, %v; want false and an error", swapped, err)
+	}
+	if store.Snapshot() != first {
+		t.Error("snapshot replaced by a failed reload")
+	}
+	if store.Failures() != 1 || store.LastError() != err.Error() {
+		t.Errorf("Failures = %d, LastError = %q; want 1 and %q", store.Failures(), store.LastError(), err)
+	}
+	if swapped, err := store.Reload(); swapped || err != nil {
+		t.Errorf("retrying the same broken files = %v, %v; want false, nil", swapped, err)
+	}
+
+	// Fixing it swaps the new catalog in and clears the error.
+	if err := os.WriteFile(file, []byte("["+plantJSON("fern")+", "+plantJSON("ivy")+"]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if swapped, err := store.Reload(); !swapped || err != nil {
+		t.Fatalf("Reload of a fixed catalog = %v, %v; want true, nil", swapped, err)
+	}
+	snap := store.Snapshot()
+	if len(snap.Plants) != 2 || snap.Version == first.Version {
+		t.Errorf("snapshot has %d plants at version %s, want 2 at a new version", len(snap.Plants), snap.Version)
+	}
+	if store.LastError() != "" {
+		t.Errorf("LastError = %q after a good reload", store.LastError())
+	}
+	if len(first.Plants) != 1 {
+		t.Errorf("previous snapshot changed to %d plants", len(first.Plants))
+	}
+
+	// A source that cannot even be listed is reported once, not on every
+	// poll, and forgotten once the files are back.
+	moved := dir + ".moved"
+	if err := os.Rename(dir, moved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reload(); err == nil || store.Failures() != 2 {
+		t.Fatalf("Reload of a missing catalog = %v with %d failures; want an error and 2", err, store.Failures())
+	}
+	lastErr := store.LastError()
+	for range 3 {
+		if swapped, err := store.Reload(); swapped || err != nil {
+			t.Errorf("retrying the missing catalog = %v, %v; want false, nil", swapped, err)
+		}
+	}
+	if store.Failures() != 2 || store.LastError() != lastErr {
+		t.Errorf("Failures = %d, LastError = %q after retries; want 2 and %q", store.Failures(), store.LastError(), lastErr)
+	}
+	if err := os.Rename(moved, dir); err != nil {
+		t.Fatal(err)
+	}
+	if swapped, err := store.Reload(); swapped || err != nil || store.LastError() != "" {
+		t.Errorf("Reload of the restored catalog = %v, %v, LastError %q; want false, nil and none", swapped, err, store.LastError())
+	}
+	if store.Snapshot() != snap {
+		t.Error("snapshot replaced while the catalog was missing")
+	}
+}
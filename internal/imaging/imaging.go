@@ -0,0 +1,118 @@
+// Package imaging produces resized variants of raster images using only
+// the standard library image packages.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder for Decode
+	"io"
+)
+
+// Variant is a named output size. Images are scaled to cover the box and
+// cropped around the centre, so every variant has an exact size.
+type Variant struct {
+	Name          string
+	Width, Height int
+}
+
+// Variants are the sizes the server offers, smallest first.
+var Variants = []Variant{
+	{"thumb", 160, 120},
+	{"card", 400, 300},
+	{"hero", 1200, 675},
+}
+
+// Lookup returns the variant with the given name.
+func Lookup(name string) (Variant, bool) {
+	for _, v := range Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+// jpegQuality balances size and artefacts for photos at card sizes.
+const jpegQuality = 82
+
+// Render decodes a JPEG or PNG from r and writes v of it as a JPEG.
+func Render(w io.Writer, r io.Reader, v Variant) error {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	out, err := Cover(src, v.Width, v.Height)
+	if err != nil {
+		return err
+	}
+	return jpeg.Encode(w, out, &jpeg.Options{Quality: jpegQuality})
+}
+
+// RenderBytes is Render on an in-memory image.
+func RenderBytes(src []byte, v Variant) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Render(&buf, bytes.NewReader(src), v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Cover scales src to fill w×h, cropping the overflow evenly from both
+// sides. Each output pixel averages the source pixels it covers, which
+// gives clean downscales without a separate blur pass. Sources too narrow
+// or flat to crop to the target aspect keep at least one pixel per side.
+func Cover(src image.Image, w, h int) (*image.RGBA, error) {
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("cover: invalid size %d×%d", w, h)
+	}
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw <= 0 || sh <= 0 {
+		return nil, fmt.Errorf("cover: empty source image %d×%d", sw, sh)
+	}
+
+	// Crop the source to the target aspect ratio.
+	crop := b
+	if sw*h > sh*w { // source is wider
+		cw := max(sh*w/h, 1)
+		crop.Min.X += (sw - cw) / 2
+		crop.Max.X = crop.Min.X + cw
+	} else {
+		ch := max(sw*h/w, 1)
+		crop.Min.Y += (sh - ch) / 2
+		crop.Max.Y = crop.Min.Y + ch
+	}
+
+	in := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(in, in.Bounds(), src, crop.Min, draw.Src)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	cw, ch := in.Bounds().Dx(), in.Bounds().Dy()
+
+	for y := 0; y < h; y++ {
+		y0 := y * ch / h
+		y1 := max((y+1)*ch/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := x * cw / w
+			x1 := max((x+1)*cw/w, x0+1)
+			var r, g, bl, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := in.Pix[sy*in.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					bl += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+			o := out.Pix[y*out.Stride+x*4:]
+			o[0], o[1], o[2], o[3] = uint8(r/n), uint8(g/n), uint8(bl/n), uint8(a/n)
+		}
+	}
+	return out, nil
+}
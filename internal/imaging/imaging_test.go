@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// solid returns a w×h image filled with c.
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestCoverSizes(t *testing.T) {
+	green := color.RGBA{40, 160, 60, 255}
+	tests := []struct {
+		name       string
+		srcW, srcH int
+		outW, outH int
+	}{
+		{"downscale", 800, 600, 400, 300},
+		{"upscale", 40, 30, 400, 300},
+		{"single pixel", 1, 1, 400, 300},
+		{"one pixel wide", 1, 50, 400, 300},
+		{"one pixel high", 50, 1, 400, 300},
+		{"very tall", 3, 5000, 1200, 675},
+		{"very wide", 5000, 3, 160, 120},
+		{"portrait target", 50, 1, 120, 160},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Cover(solid(tc.srcW, tc.srcH, green), tc.outW, tc.outH)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := out.Bounds(); got.Dx() != tc.outW || got.Dy() != tc.outH {
+				t.Fatalf("size = %dx%d, want %dx%d", got.Dx(), got.Dy(), tc.outW, tc.outH)
+			}
+			for _, p := range []image.Point{{0, 0}, {tc.outW / 2, tc.outH / 2}, {tc.outW - 1, tc.outH - 1}} {
+				if got := out.RGBAAt(p.X, p.Y); got != green {
+					t.Errorf("pixel %v = %v, want %v", p, got, green)
+				}
+			}
+		})
+	}
+}
+
+func TestCoverInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		src  image.Image
+		w, h int
+	}{
+		{"zero width", solid(10, 10, color.White), 0, 300},
+		{"zero height", solid(10, 10, color.White), 400, 0},
+		{"negative", solid(10, 10, color.White), -1, -1},
+		{"empty source", image.NewRGBA(image.Rect(0, 0, 0, 0)), 400, 300},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if out, err := Cover(tc.src, tc.w, tc.h); err == nil {
+				t.Errorf("Cover = %v, want an error", out.Bounds())
+			}
+		})
+	}
+}
+
+func TestRenderBytesNarrowPNG(t *testing.T) {
+	var src bytes.Buffer
+	if err := png.Encode(&src, solid(1, 50, color.Black)); err != nil {
+		t.Fatal(err)
+	}
+	v, _ := Lookup("card")
+	b, err := RenderBytes(src.Bytes(), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != v.Width || cfg.Height != v.Height {
+		t.Errorf("size = %dx%d, want %dx%d", cfg.Width, cfg.Height, v.Width, v.Height)
+	}
+}
@@ -0,0 +1,371 @@
+// Package metrics is a small Prometheus-compatible metrics registry:
+// counters, gauges and histograms with optional labels, plus metrics
+// computed at scrape time, written in the text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefBuckets are latency buckets in seconds, the same as Prometheus clients use.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metrics in registration order.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+type metric interface {
+	header() (name, help, typ string)
+	write(w *bufio.Writer)
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+func (r *Registry) register(m metric) {
+	name, _, _ := m.header()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo writes every metric in the text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := slices.Clone(r.metrics)
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, m := range metrics {
+		name, help, typ := m.header()
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+		m.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ContentType is the exposition format written by WriteTo.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler serves the registry for scraping.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = r.WriteTo(w)
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// desc is the part every metric shares.
+type desc struct {
+	name, help, typ string
+	labels          []string
+}
+
+func (d *desc) header() (string, string, string) { return d.name, d.help, d.typ }
+
+// vec keeps one child per distinct set of label values.
+type vec[T any] struct {
+	desc
+	mu       sync.Mutex
+	children map[string]*T
+	values   map[string][]string
+	newChild func() *T
+}
+
+func (v *vec[T]) with(values []string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	c, ok := v.children[key]
+	if !ok {
+		c = v.newChild()
+		v.children[key] = c
+		v.values[key] = slices.Clone(values)
+	}
+	return c
+}
+
+// each visits children ordered by label values.
+func (v *vec[T]) each(fn func(values []string, c *T)) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.children))
+	for k := range v.children {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	children := make([]*T, len(keys))
+	values := make([][]string, len(keys))
+	for i, k := range keys {
+		children[i], values[i] = v.children[k], v.values[k]
+	}
+	v.mu.Unlock()
+	for i := range keys {
+		fn(values[i], children[i])
+	}
+}
+
+func newVec[T any](name, help, typ string, labels []string, newChild func() *T) *vec[T] {
+	return &vec[T]{
+		desc:     desc{name: name, help: help, typ: typ, labels: labels},
+		children: map[string]*T{},
+		values:   map[string][]string{},
+		newChild: newChild,
+	}
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct{ bits atomic.Uint64 }
+
+// Inc adds one.
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add adds v, which must not be negative.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	addFloat(&c.bits, v)
+}
+
+// Value returns the current count.
+func (c *Counter) Value() float64 { return math.Float64frombits(c.bits.Load()) }
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct{ *vec[Counter] }
+
+// NewCounterVec registers a counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) CounterVec {
+	v := CounterVec{newVec(name, help, "counter", labels, func() *Counter { return new(Counter) })}
+	r.register(v)
+	return v
+}
+
+// NewCounter registers a counter without labels.
+func (r *Registry) NewCounter(name, help string) *Counter { return r.NewCounterVec(name, help).With() }
+
+// With returns the counter for the given label values, in label order.
+func (v CounterVec) With(values ...string) *Counter { return v.with(values) }
+
+func (v CounterVec) write(w *bufio.Writer) {
+	v.each(func(values []string, c *Counter) {
+		writeSample(w, v.name, v.labels, values, c.Value())
+	})
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct{ bits atomic.Uint64 }
+
+// Set replaces the value.
+func (g *Gauge) Set(v float64) { g.bits.Store(math.Float64bits(v)) }
+
+// Add adds v, which may be negative.
+func (g *Gauge) Add(v float64) { addFloat(&g.bits, v) }
+
+// Inc adds one.
+func (g *Gauge) Inc() { g.Add(1) }
+
+// Dec subtracts one.
+func (g *Gauge) Dec() { g.Add(-1) }
+
+// Value returns the current value.
+func (g *Gauge) Value() float64 { return math.Float64frombits(g.bits.Load()) }
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct{ *vec[Gauge] }
+
+// NewGaugeVec registers a gauge with the given label names.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) GaugeVec {
+	v := GaugeVec{newVec(name, help, "gauge", labels, func() *Gauge { return new(Gauge) })}
+	r.register(v)
+	return v
+}
+
+// NewGauge registers a gauge without labels.
+func (r *Registry) NewGauge(name, help string) *Gauge { return r.NewGaugeVec(name, help).With() }
+
+// With returns the gauge for the given label values, in label order.
+func (v GaugeVec) With(values ...string) *Gauge { return v.with(values) }
+
+func (v GaugeVec) write(w *bufio.Writer) {
+	v.each(func(values []string, g *Gauge) {
+		writeSample(w, v.name, v.labels, values, g.Value())
+	})
+}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	mu      sync.Mutex
+	upper   []float64 // bucket upper bounds, ascending, without +Inf
+	counts  []uint64  // per bucket, not cumulative; last is +Inf
+	sum     float64
+	samples uint64
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v) // first bucket with upper >= v
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += v
+	h.samples++
+	h.mu.Unlock()
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct{ *vec[Histogram] }
+
+// NewHistogramVec registers a histogram with the given bucket upper
+// bounds (ascending; +Inf is implied) and label names.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) HistogramVec {
+	if !slices.IsSorted(buckets) {
+		panic("metrics: buckets of " + name + " are not sorted")
+	}
+	upper := slices.Clone(buckets)
+	v := HistogramVec{newVec(name, help, "histogram", labels, func() *Histogram {
+		return &Histogram{upper: upper, counts: make([]uint64, len(upper)+1)}
+	})}
+	r.register(v)
+	return v
+}
+
+// NewHistogram registers a histogram without labels.
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	return r.NewHistogramVec(name, help, buckets).With()
+}
+
+// With returns the histogram for the given label values, in label order.
+func (v HistogramVec) With(values ...string) *Histogram { return v.with(values) }
+
+func (v HistogramVec) write(w *bufio.Writer) {
+	labels := append(slices.Clone(v.labels), "le")
+	v.each(func(values []string, h *Histogram) {
+		h.mu.Lock()
+		counts := slices.Clone(h.counts)
+		sum, samples := h.sum, h.samples
+		h.mu.Unlock()
+
+		var cum uint64
+		for i, c := range counts {
+			cum += c
+			le := math.Inf(1)
+			if i < len(h.upper) {
+				le = h.upper[i]
+			}
+			writeSample(w, v.name+"_bucket", labels, append(slices.Clone(values), formatFloat(le)), float64(cum))
+		}
+		writeSample(w, v.name+"_sum", v.labels, values, sum)
+		writeSample(w, v.name+"_count", v.labels, values, float64(samples))
+	})
+}
+
+// Sample is one labelled value reported by a scrape-time function.
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+type funcMetric struct {
+	desc
+	fn func() []Sample
+}
+
+func (f *funcMetric) write(w *bufio.Writer) {
+	for _, s := range f.fn() {
+		writeSample(w, f.name, f.labels, s.LabelValues, s.Value)
+	}
+}
+
+// NewGaugeFunc registers a gauge whose samples are computed by fn on
+// every scrape, e.g. values owned by another component.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, fn func() []Sample) {
+	r.register(&funcMetric{desc{name: name, help: help, typ: "gauge", labels: labels}, fn})
+}
+
+// NewCounterFunc is NewGaugeFunc for values that only ever increase.
+func (r *Registry) NewCounterFunc(name, help string, labels []string, fn func() []Sample) {
+	r.register(&funcMetric{desc{name: name, help: help, typ: "counter", labels: labels}, fn})
+}
+
+func addFloat(bits *atomic.Uint64, v float64) {
+	for {
+		old := bits.Load()
+		if bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(l)
+			w.WriteString(`="`)
+			w.WriteString(escapeLabel(values[i]))
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// expose returns the text exposition of r.
+func expose(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	n, err := r.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo reported %d bytes, wrote %d", n, b.Len())
+	}
+	return b.String()
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 0.5, 1})
+	for _, v := range []float64{0.05, 0.1, 0.3, 0.7, 1, 4} {
+		h.Observe(v)
+	}
+	want := strings.Join([]string{
+		"# HELP latency_seconds Latency.",
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{le="0.1"} 2`, // upper bounds are inclusive
+		`latency_seconds_bucket{le="0.5"} 3`,
+		`latency_seconds_bucket{le="1"} 5`,
+		`latency_seconds_bucket{le="+Inf"} 6`,
+		"latency_seconds_sum 6.15",
+		"latency_seconds_count 6",
+		"",
+	}, "\n")
+	if got := expose(t, r); got != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramVecLabels(t *testing.T) {
+	r := NewRegistry()
+	v := r.NewHistogramVec("results", "Results.", []float64{1}, "endpoint")
+	v.With("api").Observe(0)
+	v.With("api").Observe(3)
+	v.With("html")
+	got := expose(t, r)
+	for _, want := range []string{
+		`results_bucket{endpoint="api",le="1"} 1`,
+		`results_bucket{endpoint="api",le="+Inf"} 2`,
+		`results_count{endpoint="api"} 2`,
+		`results_bucket{endpoint="html",le="+Inf"} 0`,
+		`results_count{endpoint="html"} 0`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("exposition lacks %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("requests_total", "Requests by \\path,\nwith notes.", "path")
+	c.With(`/a"b\c` + "\nd").Inc()
+	c.With("/plain").Add(2)
+	want := strings.Join([]string{
+		`# HELP requests_total Requests by \\path,\nwith notes.`,
+		"# TYPE requests_total counter",
+		`requests_total{path="/a\"b\\c\nd"} 1`,
+		`requests_total{path="/plain"} 2`,
+		"",
+	}, "\n")
+	if got := expose(t, r); got != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("things_total", "Things.")
+	defer func() {
+		v := recover()
+		if s, _ := v.(string); !strings.Contains(s, "duplicate metric things_total") {
+			t.Errorf("recovered %v, want a duplicate metric panic", v)
+		}
+	}()
+	r.NewGaugeFunc("things_total", "Things again.", nil, func() []Sample { return nil })
+	t.Error("registering things_total twice did not panic")
+}
@@ -1,11 +1,51 @@
 package models
 
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// PlantPreferences are what a user asks for. Each field lists acceptable
+// values and a plant matches a field if it matches any one of them; an
+// empty list, or one containing "any", accepts everything.
 type PlantPreferences struct {
-	LightCondition string `json:"lightCondition"` // full-sun | partial-shade | low-light
-	CareLevel      string `json:"careLevel"`      // low | medium | high
-	PlantType      string `json:"plantType"`      // flowering | foliage | succulent | any
-	Location       string `json:"location"`       // indoor | outdoor | both
-	Size           string `json:"size"`           // small | medium | large | any
+	LightCondition Choices `json:"lightCondition"` // full-sun | partial-shade | low-light
+	CareLevel      Choices `json:"careLevel"`      // low | medium | high
+	PlantType      Choices `json:"plantType"`      // flowering | foliage | succulent | any
+	Location       Choices `json:"location"`       // indoor | outdoor | both
+	Size           Choices `json:"size"`           // small | medium | large | any
+}
+
+// Choices is a multi-valued preference. In JSON it is an array of strings,
+// never null, but a single string is accepted too.
+type Choices []string
+
+// Any reports whether c leaves its field unconstrained.
+func (c Choices) Any() bool { return len(c) == 0 || slices.Contains(c, "any") }
+
+func (c Choices) MarshalJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(c))
+}
+
+func (c *Choices) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*c = nil
+		if one != "" {
+			*c = Choices{one}
+		}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*c = many
+	return nil
 }
 
 type CareInstructions struct {
@@ -15,10 +55,22 @@ type CareInstructions struct {
 	Humidity    string `json:"humidity"`
 }
 
+// Taxonomy places a plant in the botanical hierarchy. Fields below the
+// most specific known rank are left empty.
+type Taxonomy struct {
+	Family   string `json:"family,omitempty"`
+	Genus    string `json:"genus,omitempty"`
+	Species  string `json:"species,omitempty"`  // specific epithet, e.g. "trifasciata"
+	Cultivar string `json:"cultivar,omitempty"` // without quotes, e.g. "Laurentii"
+}
+
 type Plant struct {
 	ID             string           `json:"id"`
 	Name           string           `json:"name"`
+	Aliases        []string         `json:"aliases,omitempty"` // other common names
 	ScientificName string           `json:"scientificName"`
+	Synonyms       []string         `json:"synonyms,omitempty"` // other botanical names
+	Taxonomy       Taxonomy         `json:"taxonomy"`
 	Description    string           `json:"description"`
 	Image          string           `json:"image"`
 	LightCondition []string         `json:"lightCondition"`
@@ -28,4 +80,8 @@ type Plant struct {
 	Size           string           `json:"size"`
 	Features       []string         `json:"features"`
 	Care           CareInstructions `json:"careInstructions"`
+	RetiredAt      *time.Time       `json:"retiredAt,omitempty"` // soft-deleted; hidden from listings and recommendations
 }
+
+// Retired reports whether the plant has been soft-deleted.
+func (p Plant) Retired() bool { return p.RetiredAt != nil }
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Allowed values for the enumerated plant fields.
+var (
+	LightConditions = []string{"full-sun", "partial-shade", "low-light"}
+	CareLevels      = []string{"low", "medium", "high"}
+	PlantTypes      = []string{"flowering", "foliage", "succulent"}
+	Locations       = []string{"indoor", "outdoor", "both"}
+	Sizes           = []string{"small", "medium", "large"}
+)
+
+// Validate checks a plant against the catalog schema and reports every
+// problem found, not just the first. It returns a *ValidationError.
+func (p Plant) Validate() error {
+	var e ValidationError
+	fail := func(field, v, reason string) {
+		e.Errors = append(e.Errors, FieldError{Field: field, Value: v, Reason: reason})
+	}
+	required := func(field, v string) {
+		if strings.TrimSpace(v) == "" {
+			fail(field, v, "required")
+		}
+	}
+	oneOf := func(field, v string, allowed []string) {
+		if !slices.Contains(allowed, v) {
+			e.Errors = append(e.Errors, FieldError{Field: field, Value: v, Allowed: allowed})
+		}
+	}
+
+	required("id", p.ID)
+	if strings.ContainsFunc(p.ID, func(r rune) bool { return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') }) {
+		fail("id", p.ID, "may only contain lowercase letters, digits and hyphens")
+	}
+	required("name", p.Name)
+	names := func(field string, vs []string) {
+		seen := map[string]bool{strings.ToLower(p.Name): true, strings.ToLower(p.ScientificName): true}
+		for _, v := range vs {
+			switch key := strings.ToLower(strings.TrimSpace(v)); {
+			case key == "":
+				fail(field, v, "empty name")
+			case seen[key]:
+				fail(field, v, "is listed twice or repeats the plant's name")
+			default:
+				seen[key] = true
+			}
+		}
+	}
+	names("aliases", p.Aliases)
+	names("synonyms", p.Synonyms)
+	if p.Taxonomy.Species != "" && p.Taxonomy.Genus == "" {
+		fail("taxonomy.genus", "", "required when species is set")
+	}
+	if p.Taxonomy.Cultivar != "" && p.Taxonomy.Species == "" && p.Taxonomy.Genus == "" {
+		fail("taxonomy.genus", "", "required when cultivar is set")
+	}
+	required("image", p.Image)
+	if len(p.LightCondition) == 0 {
+		fail("lightCondition", "", "at least one value required")
+	}
+	for _, l := range p.LightCondition {
+		oneOf("lightCondition", l, LightConditions)
+	}
+	oneOf("careLevel", p.CareLevel, CareLevels)
+	oneOf("plantType", p.PlantType, PlantTypes)
+	oneOf("location", p.Location, Locations)
+	oneOf("size", p.Size, Sizes)
+	if len(e.Errors) > 0 {
+		return &e
+	}
+	return nil
+}
+
+// FieldError is one invalid value of a plant or preference field: either
+// not one of Allowed, or wrong for the stated Reason.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Value   string   `json:"value"`
+	Allowed []string `json:"allowed,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+func (f FieldError) Error() string {
+	switch {
+	case f.Reason == "":
+		return fmt.Sprintf("%s: %q is not one of %s", f.Field, f.Value, strings.Join(f.Allowed, ", "))
+	case f.Value == "":
+		return f.Field + ": " + f.Reason
+	default:
+		return fmt.Sprintf("%s: %q %s", f.Field, f.Value, f.Reason)
+	}
+}
+
+// ValidationError lists every invalid value in a plant or a set of
+// preferences.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, f := range e.Errors {
+		msgs[i] = f.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns each field error on its own.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, f := range e.Errors {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Validate checks every preference value against the allowed values of its
+// field, where "any" is always allowed. It returns a *ValidationError.
+func (p PlantPreferences) Validate() error {
+	var e ValidationError
+	check := func(field string, vs Choices, allowed []string) {
+		allowed = append(slices.Clone(allowed), "any")
+		for _, v := range vs {
+			if !slices.Contains(allowed, v) {
+				e.Errors = append(e.Errors, FieldError{Field: field, Value: v, Allowed: allowed})
+			}
+		}
+	}
+	check("lightCondition", p.LightCondition, LightConditions)
+	check("careLevel", p.CareLevel, CareLevels)
+	check("plantType", p.PlantType, PlantTypes)
+	check("location", p.Location, Locations)
+	check("size", p.Size, Sizes)
+	if len(e.Errors) > 0 {
+		return &e
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package recommend
+
+import "github.com/example/leaf-love-go/internal/models"
+
+// Any is the preference value that leaves a criterion unconstrained.
+const Any = "any"
+
+// Facets counts, for each value of each preference field, how many plants
+// Rank would recommend if only that field were changed to that single
+// value. The "any" entries count the field left unset. As fields match any
+// of their values, an option counting zero adds nothing when combined with
+// others either. Features counts the tags of the plants recommended for
+// the preferences as given.
+type Facets struct {
+	LightCondition map[string]int `json:"lightCondition"`
+	CareLevel      map[string]int `json:"careLevel"`
+	PlantType      map[string]int `json:"plantType"`
+	Location       map[string]int `json:"location"`
+	Size           map[string]int `json:"size"`
+	Features       map[string]int `json:"features"`
+}
+
+// ComputeFacets returns the facets of plants for p.
+func ComputeFacets(plants []models.Plant, p models.PlantPreferences) Facets {
+	count := func(values []string, set func(*models.PlantPreferences, string)) map[string]int {
+		out := make(map[string]int, len(values)+1)
+		for _, v := range append([]string{Any}, values...) {
+			q := p
+			set(&q, v)
+			out[v] = len(Rank(plants, q))
+		}
+		return out
+	}
+	f := Facets{
+		LightCondition: count(models.LightConditions, func(q *models.PlantPreferences, v string) { q.LightCondition = models.Choices{v} }),
+		CareLevel:      count(models.CareLevels, func(q *models.PlantPreferences, v string) { q.CareLevel = models.Choices{v} }),
+		PlantType:      count(models.PlantTypes, func(q *models.PlantPreferences, v string) { q.PlantType = models.Choices{v} }),
+		Location:       count(models.Locations, func(q *models.PlantPreferences, v string) { q.Location = models.Choices{v} }),
+		Size:           count(models.Sizes, func(q *models.PlantPreferences, v string) { q.Size = models.Choices{v} }),
+		Features:       make(map[string]int),
+	}
+	for _, rec := range Rank(plants, p) {
+		for _, tag := range rec.Features {
+			f.Features[tag]++
+		}
+	}
+	return f
+}
@@ -0,0 +1,70 @@
+package recommend
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+var (
+	cactus = plant("cactus", "full-sun", "low", "succulent", "both", "small", "drought-tolerant", "air-purifying")
+	rose   = plant("rose", "full-sun", "high", "flowering", "outdoor", "medium", "fragrant")
+)
+
+func TestComputeFacets(t *testing.T) {
+	plants := []models.Plant{
+		plant("fern", "low-light", "medium", "foliage", "indoor", "medium", "air-purifying"),
+		cactus, // full sun, both
+		rose,   // full sun, outdoor
+	}
+	// Indoors, rose misses on location and drops out; wanting high care,
+	// so does cactus, two steps away at low.
+	f := ComputeFacets(plants, models.PlantPreferences{Location: models.Choices{"indoor"}})
+
+	tests := []struct {
+		name      string
+		got, want map[string]int
+	}{
+		{"lightCondition", f.LightCondition, map[string]int{Any: 2, "full-sun": 1, "partial-shade": 2, "low-light": 1}},
+		{"location", f.Location, map[string]int{Any: 3, "indoor": 2, "outdoor": 2, "both": 3}},
+		{"careLevel", f.CareLevel, map[string]int{Any: 2, "low": 2, "medium": 2, "high": 1}},
+		{"features", f.Features, map[string]int{"air-purifying": 2, "drought-tolerant": 1}},
+	}
+	for _, tc := range tests {
+		if !maps.Equal(tc.got, tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+	for _, m := range []map[string]int{f.PlantType, f.Size} {
+		if len(m) != 4 || m[Any] != 2 {
+			t.Errorf("facet %v: want every value plus any counted", m)
+		}
+	}
+}
+
+func TestComputeFacetsNarrowed(t *testing.T) {
+	plants := []models.Plant{
+		plant("fern", "low-light", "medium", "foliage", "indoor", "medium", "air-purifying"),
+		cactus,
+		rose,
+	}
+	// Only cactus is a succulent. Low light is two steps from its full
+	// sun, so choosing it as well would leave nothing.
+	f := ComputeFacets(plants, models.PlantPreferences{PlantType: models.Choices{"succulent"}})
+
+	tests := []struct {
+		name      string
+		got, want map[string]int
+	}{
+		{"plantType", f.PlantType, map[string]int{Any: 3, "foliage": 1, "flowering": 1, "succulent": 1}},
+		{"lightCondition", f.LightCondition, map[string]int{Any: 1, "full-sun": 1, "partial-shade": 1, "low-light": 0}},
+		{"careLevel", f.CareLevel, map[string]int{Any: 1, "low": 1, "medium": 1, "high": 0}},
+		{"features", f.Features, map[string]int{"air-purifying": 1, "drought-tolerant": 1}},
+	}
+	for _, tc := range tests {
+		if !maps.Equal(tc.got, tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
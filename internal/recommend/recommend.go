@@ -0,0 +1,316 @@
+// Package recommend ranks plants against a set of preferences.
+//
+// Every plant is scored per criterion (0..1), the criterion scores are
+// weighted and summed, and the total is reported as a match percentage.
+// Plants that miss a stated preference outright, or fall below MinMatch,
+// are dropped; the rest are sorted best-first.
+package recommend
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// Criterion names, as they appear in breakdowns.
+const (
+	CriterionLight    = "light"
+	CriterionCare     = "care"
+	CriterionType     = "type"
+	CriterionLocation = "location"
+	CriterionSize     = "size"
+)
+
+// Weights controls how much each criterion contributes to the total score.
+var Weights = map[string]float64{
+	CriterionLight:    3,
+	CriterionCare:     2,
+	CriterionType:     2,
+	CriterionLocation: 3,
+	CriterionSize:     1,
+}
+
+// MinMatch is the lowest match percentage that still counts as a recommendation.
+// It bounds how much partial credit adds up: half credit on light and on
+// care still passes, half credit on light and location does not.
+const MinMatch = 75
+
+// Ordered scales used for partial credit on near values.
+var (
+	lightScale = []string{"full-sun", "partial-shade", "low-light"}
+	careScale  = []string{"low", "medium", "high"}
+	sizeScale  = []string{"small", "medium", "large"}
+)
+
+// Outcomes of a criterion, from best to worst.
+const (
+	OutcomeAny     = "any"     // no preference given
+	OutcomeMatch   = "match"   // full credit
+	OutcomePartial = "partial" // a neighbouring value, half credit
+	OutcomeMiss    = "miss"    // no credit
+)
+
+// CriterionScore is the outcome of one criterion for one plant.
+type CriterionScore struct {
+	Criterion string  `json:"criterion"`
+	Weight    float64 `json:"weight"`
+	Score     float64 `json:"score"` // 0..1
+	Matched   bool    `json:"matched"`
+	Outcome   string  `json:"outcome"`
+	Reason    string  `json:"reason"` // e.g. "matches partial-shade", "plant is both"
+}
+
+// Recommendation is a plant together with how well it fits the preferences.
+type Recommendation struct {
+	models.Plant
+	Score        float64          `json:"score"`
+	MatchPercent int              `json:"matchPercent"`
+	Breakdown    []CriterionScore `json:"breakdown"`
+	Missed       []string         `json:"missed,omitempty"` // criteria without full credit
+}
+
+// NearMissMin is the lowest match percentage reported by NearMisses.
+const NearMissMin = 50
+
+// Rank scores every plant and returns those recommended for p, best match
+// first and by name within equal scores.
+func Rank(plants []models.Plant, p models.PlantPreferences) []Recommendation {
+	out := make([]Recommendation, 0, len(plants))
+	for _, plant := range plants {
+		rec := Score(plant, p)
+		if recommended(rec) {
+			out = append(out, rec)
+		}
+	}
+	slices.SortFunc(out, byScore)
+	return out
+}
+
+// NearMisses returns up to n plants that Rank left out but that scored at
+// least NearMissMin, best first. Their Missed field names the criteria
+// that let them down.
+func NearMisses(plants []models.Plant, p models.PlantPreferences, n int) []Recommendation {
+	var out []Recommendation
+	for _, plant := range plants {
+		rec := Score(plant, p)
+		if rec.MatchPercent >= NearMissMin && !recommended(rec) {
+			out = append(out, rec)
+		}
+	}
+	slices.SortFunc(out, byScore)
+	return out[:min(n, len(out))]
+}
+
+// recommended reports whether rec reaches MinMatch without missing any
+// stated preference outright. A preference is a requirement: a plant of
+// the wrong type is never recommended, however well it fits otherwise.
+func recommended(rec Recommendation) bool {
+	if rec.MatchPercent < MinMatch {
+		return false
+	}
+	for _, c := range rec.Breakdown {
+		if c.Outcome == OutcomeMiss {
+			return false
+		}
+	}
+	return true
+}
+
+// byScore orders recommendations best-first, then by name.
+func byScore(a, b Recommendation) int {
+	if c := cmp.Compare(b.Score, a.Score); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Name, b.Name)
+}
+
+// Score evaluates a single plant against the preferences.
+func Score(plant models.Plant, p models.PlantPreferences) Recommendation {
+	light, lightBy := scoreLight(plant.LightCondition, p.LightCondition)
+	care, careBy := scoreScale(careScale, plant.CareLevel, p.CareLevel)
+	typ, typeBy := scoreExact(plant.PlantType, p.PlantType)
+	loc, locBy := scoreLocation(plant.Location, p.Location)
+	size, sizeBy := scoreScale(sizeScale, plant.Size, p.Size)
+	breakdown := []CriterionScore{
+		criterion(CriterionLight, light, lightBy, p.LightCondition, strings.Join(plant.LightCondition, "/")),
+		criterion(CriterionCare, care, careBy, p.CareLevel, plant.CareLevel),
+		criterion(CriterionType, typ, typeBy, p.PlantType, plant.PlantType),
+		criterion(CriterionLocation, loc, locBy, p.Location, plant.Location),
+		criterion(CriterionSize, size, sizeBy, p.Size, plant.Size),
+	}
+
+	var got, total float64
+	var missed []string
+	for _, c := range breakdown {
+		got += c.Weight * c.Score
+		total += c.Weight
+		if c.Score < 1 {
+			missed = append(missed, c.Criterion)
+		}
+	}
+	score := 0.0
+	if total > 0 {
+		score = got / total
+	}
+	return Recommendation{
+		Plant:        plant,
+		Score:        score,
+		MatchPercent: int(score*100 + 0.5),
+		Breakdown:    breakdown,
+		Missed:       missed,
+	}
+}
+
+// criterion builds the score of one criterion and explains it. by is the
+// wanted value that earned the score, want all of them and have the
+// plant's value.
+func criterion(name string, score float64, by string, want models.Choices, have string) CriterionScore {
+	c := CriterionScore{Criterion: name, Weight: Weights[name], Score: score, Matched: score == 1}
+	switch {
+	case want.Any():
+		c.Outcome, c.Reason = OutcomeAny, "any"
+	case score == 1 && name == CriterionLocation && have == "both" && by != "both":
+		c.Outcome, c.Reason = OutcomeMatch, "plant is both"
+	case score == 1:
+		c.Outcome, c.Reason = OutcomeMatch, "matches "+by
+	case score > 0:
+		c.Outcome, c.Reason = OutcomePartial, fmt.Sprintf("plant is %s, near %s", have, by)
+	default:
+		c.Outcome, c.Reason = OutcomeMiss, fmt.Sprintf("plant is %s, wanted %s", have, strings.Join(want, " or "))
+	}
+	return c
+}
+
+// bestOf returns the best credit any wanted value earns and the value
+// that earned it, or full credit if want is unconstrained.
+func bestOf(want models.Choices, credit func(w string) float64) (float64, string) {
+	if want.Any() {
+		return 1, ""
+	}
+	best, by := 0.0, ""
+	for _, w := range want {
+		if c := credit(w); c > best {
+			best, by = c, w
+		}
+	}
+	return best, by
+}
+
+// scoreLight gives full credit if the plant tolerates a requested light and
+// half credit if it tolerates a neighbouring light level.
+func scoreLight(have []string, want models.Choices) (float64, string) {
+	return bestOf(want, func(w string) float64 {
+		best := 0.0
+		for _, h := range have {
+			best = max(best, scaleCredit(lightScale, h, w))
+		}
+		return best
+	})
+}
+
+func scoreScale(scale []string, have string, want models.Choices) (float64, string) {
+	return bestOf(want, func(w string) float64 { return scaleCredit(scale, have, w) })
+}
+
+func scaleCredit(scale []string, have, want string) float64 {
+	if have == want {
+		return 1
+	}
+	i, j := slices.Index(scale, have), slices.Index(scale, want)
+	if i < 0 || j < 0 {
+		return 0
+	}
+	if i-j == 1 || j-i == 1 {
+		return 0.5
+	}
+	return 0
+}
+
+func scoreExact(have string, want models.Choices) (float64, string) {
+	return bestOf(want, func(w string) float64 {
+		if have == w {
+			return 1
+		}
+		return 0
+	})
+}
+
+func scoreLocation(have string, want models.Choices) (float64, string) {
+	return bestOf(want, func(w string) float64 {
+		if w == "both" || have == "both" || have == w {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Candidates returns the narrowest repository filter that still contains
+// every plant Rank could recommend for p. Every stated preference must
+// score above zero, so each is pushed down to storage as the values that
+// earn any credit; the MinMatch threshold is left to Rank.
+func Candidates(p models.PlantPreferences) data.PlantFilter {
+	var f data.PlantFilter
+	if !p.LightCondition.Any() {
+		f.LightConditions = credited(lightScale, p.LightCondition)
+	}
+	if !p.CareLevel.Any() {
+		f.CareLevels = credited(careScale, p.CareLevel)
+	}
+	if !p.PlantType.Any() {
+		f.PlantTypes = slices.Clone(p.PlantType)
+	}
+	if !p.Location.Any() && !slices.Contains(p.Location, "both") {
+		f.Locations = append(slices.Clone(p.Location), "both")
+	}
+	if !p.Size.Any() {
+		f.Sizes = credited(sizeScale, p.Size)
+	}
+	return f
+}
+
+// credited lists the wanted values and the scale values that earn any
+// credit against one of them.
+func credited(scale []string, want models.Choices) []string {
+	out := slices.Clone(want)
+	for _, v := range scale {
+		if !slices.Contains(out, v) && slices.ContainsFunc(want, func(w string) bool { return scaleCredit(scale, v, w) > 0 }) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Similar scores plants against the profile of target and returns the best
+// n at or above MinMatch, excluding target itself. Unlike Rank, a plant
+// may differ from target on any criterion as long as the rest make up for
+// it. A target with several light conditions is compared on each and
+// every plant keeps its best score.
+func Similar(target models.Plant, plants []models.Plant, n int) []Recommendation {
+	best := map[string]Recommendation{}
+	for _, light := range target.LightCondition {
+		profile := models.PlantPreferences{
+			LightCondition: models.Choices{light},
+			CareLevel:      models.Choices{target.CareLevel},
+			PlantType:      models.Choices{target.PlantType},
+			Location:       models.Choices{target.Location},
+			Size:           models.Choices{target.Size},
+		}
+		for _, plant := range plants {
+			rec := Score(plant, profile)
+			if rec.ID != target.ID && rec.MatchPercent >= MinMatch && rec.Score > best[rec.ID].Score {
+				best[rec.ID] = rec
+			}
+		}
+	}
+
+	out := make([]Recommendation, 0, len(best))
+	for _, rec := range best {
+		out = append(out, rec)
+	}
+	slices.SortFunc(out, byScore)
+	return out[:min(n, len(out))]
+}
@@ -0,0 +1,303 @@
+package recommend
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// plant builds a test plant with one light condition.
+func plant(id, light, care, typ, location, size string, features ...string) models.Plant {
+	return models.Plant{ID: id, Name: id, LightCondition: []string{light},
+		CareLevel: care, PlantType: typ, Location: location, Size: size, Features: features}
+}
+
+// catalog loads the repository's plant catalog.
+func catalog(t *testing.T) []models.Plant {
+	t.Helper()
+	store, err := data.NewStore("../../catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store.Snapshot().Plants
+}
+
+func ids(recs []Recommendation) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func breakdownOf(rec Recommendation, criterion string) CriterionScore {
+	for _, c := range rec.Breakdown {
+		if c.Criterion == criterion {
+			return c
+		}
+	}
+	return CriterionScore{}
+}
+
+// fern is matched in full by exact.
+var (
+	fern  = plant("fern", "low-light", "medium", "foliage", "indoor", "medium")
+	exact = models.PlantPreferences{
+		LightCondition: models.Choices{"low-light"},
+		CareLevel:      models.Choices{"medium"},
+		PlantType:      models.Choices{"foliage"},
+		Location:       models.Choices{"indoor"},
+		Size:           models.Choices{"medium"},
+	}
+)
+
+func TestScoreWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		plant   models.Plant
+		percent int
+		missed  []string
+	}{
+		{"all match", fern, 100, nil},
+		{"light miss", plant("p", "full-sun", "medium", "foliage", "indoor", "medium"), 73, []string{CriterionLight}},                       // 8/11
+		{"care miss", plant("p", "low-light", "x", "foliage", "indoor", "medium"), 82, []string{CriterionCare}},                             // 9/11
+		{"type miss", plant("p", "low-light", "medium", "succulent", "indoor", "medium"), 82, []string{CriterionType}},                      // 9/11
+		{"location miss", plant("p", "low-light", "medium", "foliage", "outdoor", "medium"), 73, []string{CriterionLocation}},               // 8/11
+		{"size miss", plant("p", "low-light", "medium", "foliage", "indoor", "x"), 91, []string{CriterionSize}},                             // 10/11
+		{"care and size partial", plant("p", "low-light", "low", "foliage", "indoor", "large"), 86, []string{CriterionCare, CriterionSize}}, // 9.5/11
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := Score(tc.plant, exact)
+			if rec.MatchPercent != tc.percent {
+				t.Errorf("MatchPercent = %d, want %d", rec.MatchPercent, tc.percent)
+			}
+			if !slices.Equal(rec.Missed, tc.missed) {
+				t.Errorf("Missed = %v, want %v", rec.Missed, tc.missed)
+			}
+			for _, c := range rec.Breakdown {
+				if c.Weight != Weights[c.Criterion] {
+					t.Errorf("%s weight = %v, want %v", c.Criterion, c.Weight, Weights[c.Criterion])
+				}
+			}
+		})
+	}
+
+	rec := Score(fern, models.PlantPreferences{})
+	if rec.MatchPercent != 100 {
+		t.Errorf("no preferences: MatchPercent = %d, want 100", rec.MatchPercent)
+	}
+	for _, c := range rec.Breakdown {
+		if c.Outcome != OutcomeAny {
+			t.Errorf("no preferences: %s outcome = %s, want any", c.Criterion, c.Outcome)
+		}
+	}
+}
+
+func TestScorePartialCredit(t *testing.T) {
+	tests := []struct {
+		name      string
+		plant     models.Plant
+		prefs     models.PlantPreferences
+		criterion string
+		score     float64
+		outcome   string
+		reason    string
+	}{
+		{"light neighbour", plant("p", "partial-shade", "low", "foliage", "indoor", "small"),
+			models.PlantPreferences{LightCondition: models.Choices{"low-light"}}, CriterionLight, 0.5, OutcomePartial, "plant is partial-shade, near low-light"},
+		{"light two steps", plant("p", "full-sun", "low", "foliage", "indoor", "small"),
+			models.PlantPreferences{LightCondition: models.Choices{"low-light"}}, CriterionLight, 0, OutcomeMiss, "plant is full-sun, wanted low-light"},
+		{"light any tolerated", models.Plant{LightCondition: []string{"full-sun", "low-light"}},
+			models.PlantPreferences{LightCondition: models.Choices{"low-light"}}, CriterionLight, 1, OutcomeMatch, "matches low-light"},
+		{"care neighbour above", plant("p", "low-light", "medium", "foliage", "indoor", "small"),
+			models.PlantPreferences{CareLevel: models.Choices{"high"}}, CriterionCare, 0.5, OutcomePartial, "plant is medium, near high"},
+		{"care neighbour below", plant("p", "low-light", "medium", "foliage", "indoor", "small"),
+			models.PlantPreferences{CareLevel: models.Choices{"low"}}, CriterionCare, 0.5, OutcomePartial, "plant is medium, near low"},
+		{"care two steps", plant("p", "low-light", "high", "foliage", "indoor", "small"),
+			models.PlantPreferences{CareLevel: models.Choices{"low"}}, CriterionCare, 0, OutcomeMiss, "plant is high, wanted low"},
+		{"care best of several", plant("p", "low-light", "high", "foliage", "indoor", "small"),
+			models.PlantPreferences{CareLevel: models.Choices{"low", "medium"}}, CriterionCare, 0.5, OutcomePartial, "plant is high, near medium"},
+		{"size neighbour", plant("p", "low-light", "low", "foliage", "indoor", "large"),
+			models.PlantPreferences{Size: models.Choices{"medium"}}, CriterionSize, 0.5, OutcomePartial, "plant is large, near medium"},
+		{"type has no neighbours", plant("p", "low-light", "low", "succulent", "indoor", "small"),
+			models.PlantPreferences{PlantType: models.Choices{"foliage"}}, CriterionType, 0, OutcomeMiss, "plant is succulent, wanted foliage"},
+		{"plant is both", plant("p", "low-light", "low", "foliage", "both", "small"),
+			models.PlantPreferences{Location: models.Choices{"indoor"}}, CriterionLocation, 1, OutcomeMatch, "plant is both"},
+		{"want both", plant("p", "low-light", "low", "foliage", "outdoor", "small"),
+			models.PlantPreferences{Location: models.Choices{"both"}}, CriterionLocation, 1, OutcomeMatch, "matches both"},
+		{"location has no neighbours", plant("p", "low-light", "low", "foliage", "outdoor", "small"),
+			models.PlantPreferences{Location: models.Choices{"indoor"}}, CriterionLocation, 0, OutcomeMiss, "plant is outdoor, wanted indoor"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := breakdownOf(Score(tc.plant, tc.prefs), tc.criterion)
+			if c.Score != tc.score || c.Outcome != tc.outcome || c.Reason != tc.reason {
+				t.Errorf("%s = %v %s %q, want %v %s %q", tc.criterion, c.Score, c.Outcome, c.Reason, tc.score, tc.outcome, tc.reason)
+			}
+			if c.Matched != (tc.score == 1) {
+				t.Errorf("Matched = %v with score %v", c.Matched, c.Score)
+			}
+		})
+	}
+}
+
+func TestRankMinMatch(t *testing.T) {
+	plants := []models.Plant{
+		fern, // 100
+		plant("size-partial", "low-light", "medium", "foliage", "indoor", "large"),                 // 95
+		plant("size-miss", "low-light", "medium", "foliage", "indoor", "x"),                        // 91, but a miss
+		plant("type-miss", "low-light", "medium", "succulent", "indoor", "medium"),                 // 82, but a miss
+		plant("light-partial-care-partial", "partial-shade", "low", "foliage", "indoor", "medium"), // 77
+		plant("light-partial-location-miss", "partial-shade", "medium", "foliage", "x", "medium"),  // 59
+		plant("light-miss", "full-sun", "medium", "foliage", "indoor", "medium"),                   // 73
+	}
+	got := Rank(plants, exact)
+	want := []string{"fern", "size-partial", "light-partial-care-partial"}
+	if !slices.Equal(ids(got), want) {
+		t.Errorf("Rank = %v, want %v", ids(got), want)
+	}
+	for _, rec := range got {
+		if rec.MatchPercent < MinMatch {
+			t.Errorf("%s: %d%% is below MinMatch", rec.ID, rec.MatchPercent)
+		}
+	}
+
+	near := NearMisses(plants, exact, 3)
+	if want := []string{"size-miss", "type-miss", "light-miss"}; !slices.Equal(ids(near), want) {
+		t.Errorf("NearMisses = %v, want %v", ids(near), want)
+	}
+}
+
+// TestRankExcludesMisses checks that a stated preference is required:
+// plants of another type are dropped even though the rest of their
+// profile would carry them past MinMatch.
+func TestRankExcludesMisses(t *testing.T) {
+	prefs := models.PlantPreferences{
+		PlantType:      models.Choices{"succulent"},
+		LightCondition: models.Choices{"partial-shade"},
+		Location:       models.Choices{"indoor"},
+	}
+	plants := []models.Plant{
+		plant("aloe", "partial-shade", "low", "succulent", "indoor", "small"),
+		plant("calathea", "partial-shade", "medium", "foliage", "indoor", "medium"), // 82
+		plant("orchid", "partial-shade", "high", "flowering", "both", "small"),      // 82
+	}
+	if got := ids(Rank(plants, prefs)); !slices.Equal(got, []string{"aloe"}) {
+		t.Errorf("Rank = %v, want only the succulent", got)
+	}
+	for _, rec := range NearMisses(plants, prefs, 3) {
+		if rec.MatchPercent < MinMatch || !slices.Contains(rec.Missed, CriterionType) {
+			t.Errorf("near miss %s: %d%%, missed %v; want it dropped for its type alone", rec.ID, rec.MatchPercent, rec.Missed)
+		}
+	}
+}
+
+func TestRankTieBreak(t *testing.T) {
+	plants := []models.Plant{
+		plant("zz-partial", "partial-shade", "medium", "foliage", "indoor", "medium"),
+		plant("b-exact", "low-light", "medium", "foliage", "indoor", "medium"),
+		plant("aa-partial", "partial-shade", "medium", "foliage", "indoor", "medium"),
+		plant("a-exact", "low-light", "medium", "foliage", "indoor", "medium"),
+	}
+	want := []string{"a-exact", "b-exact", "aa-partial", "zz-partial"}
+	if got := ids(Rank(plants, exact)); !slices.Equal(got, want) {
+		t.Errorf("Rank = %v, want best first, then by name: %v", got, want)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs models.PlantPreferences
+		want  data.PlantFilter
+	}{
+		{"no preferences", models.PlantPreferences{}, data.PlantFilter{}},
+		{"light widens to neighbours", models.PlantPreferences{LightCondition: models.Choices{"low-light"}},
+			data.PlantFilter{LightConditions: []string{"low-light", "partial-shade"}}},
+		{"light any", models.PlantPreferences{LightCondition: models.Choices{"any"}}, data.PlantFilter{}},
+		{"location adds both", models.PlantPreferences{Location: models.Choices{"indoor"}},
+			data.PlantFilter{Locations: []string{"indoor", "both"}}},
+		{"location both is any", models.PlantPreferences{Location: models.Choices{"both"}}, data.PlantFilter{}},
+		{"type has no neighbours", models.PlantPreferences{PlantType: models.Choices{"succulent"}},
+			data.PlantFilter{PlantTypes: []string{"succulent"}}},
+		{"care widens to neighbours", models.PlantPreferences{CareLevel: models.Choices{"low"}},
+			data.PlantFilter{CareLevels: []string{"low", "medium"}}},
+		{"every preference pushed down", exact, data.PlantFilter{
+			LightConditions: []string{"low-light", "partial-shade"}, CareLevels: []string{"medium", "low", "high"},
+			PlantTypes: []string{"foliage"}, Locations: []string{"indoor", "both"}, Sizes: []string{"medium", "small", "large"},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Candidates(tc.prefs)
+			if !slices.Equal(got.LightConditions, tc.want.LightConditions) || !slices.Equal(got.CareLevels, tc.want.CareLevels) ||
+				!slices.Equal(got.PlantTypes, tc.want.PlantTypes) || !slices.Equal(got.Locations, tc.want.Locations) ||
+				!slices.Equal(got.Sizes, tc.want.Sizes) {
+				t.Errorf("Candidates = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestCandidatesKeepEveryRecommendation ranks the catalog for every
+// combination of single preferences, and a few multi-valued ones, and
+// checks that ranking only the candidates gives the same result.
+func TestCandidatesKeepEveryRecommendation(t *testing.T) {
+	plants := catalog(t)
+	with := func(values []string) []models.Choices {
+		out := []models.Choices{nil}
+		for _, v := range values {
+			out = append(out, models.Choices{v})
+		}
+		return out
+	}
+	var prefs []models.PlantPreferences
+	for _, l := range append(with(models.LightConditions), models.Choices{"full-sun", "low-light"}) {
+		for _, c := range append(with(models.CareLevels), models.Choices{"low", "high"}) {
+			for _, ty := range with(models.PlantTypes) {
+				for _, lo := range append(with(models.Locations), models.Choices{"indoor", "outdoor"}) {
+					for _, s := range with(models.Sizes) {
+						prefs = append(prefs, models.PlantPreferences{LightCondition: l, CareLevel: c, PlantType: ty, Location: lo, Size: s})
+					}
+				}
+			}
+		}
+	}
+
+	for _, p := range prefs {
+		f := Candidates(p)
+		var candidates []models.Plant
+		for _, plant := range plants {
+			if f.Match(plant) {
+				candidates = append(candidates, plant)
+			}
+		}
+		want, got := Rank(plants, p), Rank(candidates, p)
+		if !slices.Equal(ids(got), ids(want)) {
+			t.Fatalf("%+v: ranking candidates %+v gives %v, ranking the catalog %v", p, f, ids(got), ids(want))
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	target := models.Plant{ID: "target", Name: "target", LightCondition: []string{"full-sun", "low-light"},
+		CareLevel: "low", PlantType: "foliage", Location: "indoor", Size: "small"}
+	plants := []models.Plant{
+		target,
+		plant("shade-twin", "low-light", "low", "foliage", "indoor", "small"),
+		plant("sun-twin", "full-sun", "low", "foliage", "both", "small"),
+		plant("bigger", "low-light", "low", "foliage", "indoor", "medium"),
+		plant("unlike", "partial-shade", "high", "succulent", "outdoor", "large"),
+	}
+
+	got := Similar(target, plants, 3)
+	if want := []string{"shade-twin", "sun-twin", "bigger"}; !slices.Equal(ids(got), want) {
+		t.Errorf("Similar = %v, want %v", ids(got), want)
+	}
+	if got := Similar(target, plants, 1); !slices.Equal(ids(got), []string{"shade-twin"}) {
+		t.Errorf("Similar(n=1) = %v, want [shade-twin]", ids(got))
+	}
+}
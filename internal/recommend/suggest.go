@@ -0,0 +1,118 @@
+package recommend
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// MaxSuggestions caps how many relaxations Suggest returns.
+const MaxSuggestions = 4
+
+// Suggestion is a relaxed set of preferences that gives recommendations
+// where the original gave none.
+type Suggestion struct {
+	Label       string                  `json:"label"` // e.g. "Allow medium care"
+	Plants      int                     `json:"plants"`
+	Preferences models.PlantPreferences `json:"preferences"`
+}
+
+// relaxation is one way to loosen one field.
+type relaxation struct {
+	field int
+	label string
+	apply func(*models.PlantPreferences)
+}
+
+// prefField describes a preference field for relaxing it.
+type prefField struct {
+	values   []string              // values that can be added
+	anyLabel string                // label for dropping the field
+	allow    func(v string) string // label for adding value v
+	choices  func(*models.PlantPreferences) *models.Choices
+}
+
+var prefFields = []prefField{
+	{models.LightConditions, "any light", func(v string) string { return strings.ReplaceAll(v, "-", " ") },
+		func(p *models.PlantPreferences) *models.Choices { return &p.LightCondition }},
+	{models.CareLevels, "any care level", func(v string) string { return v + " care" },
+		func(p *models.PlantPreferences) *models.Choices { return &p.CareLevel }},
+	{models.PlantTypes, "any plant type", func(v string) string { return v + " plants" },
+		func(p *models.PlantPreferences) *models.Choices { return &p.PlantType }},
+	// "both" is not offered as a location: it already means any.
+	{[]string{"indoor", "outdoor"}, "any location", func(v string) string { return v + " plants" },
+		func(p *models.PlantPreferences) *models.Choices { return &p.Location }},
+	{models.Sizes, "any size", func(v string) string { return v + " plants" },
+		func(p *models.PlantPreferences) *models.Choices { return &p.Size }},
+}
+
+// relaxations lists every way to loosen a single field of p: adding one
+// more accepted value, or dropping the field altogether.
+func relaxations(p models.PlantPreferences) []relaxation {
+	var out []relaxation
+	for fi, f := range prefFields {
+		current := *f.choices(&p)
+		if current.Any() {
+			continue
+		}
+		for _, v := range f.values {
+			if slices.Contains(current, v) {
+				continue
+			}
+			out = append(out, relaxation{fi, "allow " + f.allow(v), func(q *models.PlantPreferences) {
+				c := f.choices(q)
+				*c = append(slices.Clone(*c), v)
+			}})
+		}
+		out = append(out, relaxation{fi, f.anyLabel, func(q *models.PlantPreferences) { *f.choices(q) = nil }})
+	}
+	return out
+}
+
+// Suggest returns up to MaxSuggestions ways to relax p that give
+// recommendations, or nil if p already gives some. Single relaxations are
+// tried first and pairs on different fields only if none helps. The
+// suggestions giving the most plants come first.
+func Suggest(plants []models.Plant, p models.PlantPreferences) []Suggestion {
+	if len(Rank(plants, p)) > 0 {
+		return nil
+	}
+	try := func(rs ...relaxation) (Suggestion, bool) {
+		q := p
+		labels := make([]string, len(rs))
+		for i, r := range rs {
+			r.apply(&q)
+			labels[i] = r.label
+		}
+		n := len(Rank(plants, q))
+		label := strings.Join(labels, " and ")
+		label = strings.ToUpper(label[:1]) + label[1:]
+		return Suggestion{Label: label, Plants: n, Preferences: q}, n > 0
+	}
+
+	rs := relaxations(p)
+	var out []Suggestion
+	for _, r := range rs {
+		if s, ok := try(r); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		for i, a := range rs {
+			for _, b := range rs[i+1:] {
+				if a.field == b.field {
+					continue
+				}
+				if s, ok := try(a, b); ok {
+					out = append(out, s)
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Suggestion) int {
+		return cmp.Compare(b.Plants, a.Plants)
+	})
+	return out[:min(len(out), MaxSuggestions)]
+}
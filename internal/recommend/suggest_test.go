@@ -0,0 +1,70 @@
+package recommend
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+func labels(ss []Suggestion) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = s.Label
+	}
+	return out
+}
+
+func TestSuggest(t *testing.T) {
+	plants := []models.Plant{fern, cactus, rose}
+
+	if got := Suggest(plants, exact); got != nil {
+		t.Errorf("Suggest with matches = %v, want nil", labels(got))
+	}
+
+	tests := []struct {
+		name   string
+		plants []models.Plant
+		prefs  models.PlantPreferences
+		first  string
+	}{
+		{"single relaxation", plants, models.PlantPreferences{
+			LightCondition: models.Choices{"low-light"}, CareLevel: models.Choices{"high"},
+			PlantType: models.Choices{"flowering"}, Location: models.Choices{"outdoor"},
+		}, "Allow full sun"},
+		// No single field relaxes far enough, so pairs are tried.
+		{"pair of relaxations", []models.Plant{rose}, models.PlantPreferences{
+			LightCondition: models.Choices{"low-light"}, Location: models.Choices{"indoor"},
+		}, "Allow full sun and allow outdoor plants"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(Rank(tc.plants, tc.prefs)) != 0 {
+				t.Fatal("fixture already has recommendations")
+			}
+			got := Suggest(tc.plants, tc.prefs)
+			if len(got) == 0 || len(got) > MaxSuggestions {
+				t.Fatalf("Suggest = %v, want 1 to %d suggestions", labels(got), MaxSuggestions)
+			}
+			if got[0].Label != tc.first {
+				t.Errorf("first suggestion = %q, want %q (all: %v)", got[0].Label, tc.first, labels(got))
+			}
+			for i, s := range got {
+				if n := len(Rank(tc.plants, s.Preferences)); n == 0 || n != s.Plants {
+					t.Errorf("%q: Plants = %d, but its preferences give %d", s.Label, s.Plants, n)
+				}
+				if i > 0 && s.Plants > got[i-1].Plants {
+					t.Errorf("%q (%d plants) comes after %q (%d)", s.Label, s.Plants, got[i-1].Label, got[i-1].Plants)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestLeavesPreferencesAlone(t *testing.T) {
+	prefs := models.PlantPreferences{LightCondition: models.Choices{"low-light"}, Location: models.Choices{"indoor"}}
+	Suggest([]models.Plant{rose}, prefs)
+	if !slices.Equal(prefs.LightCondition, models.Choices{"low-light"}) || !slices.Equal(prefs.Location, models.Choices{"indoor"}) {
+		t.Errorf("Suggest changed its input to %+v", prefs)
+	}
+}
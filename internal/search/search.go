@@ -0,0 +1,384 @@
+// Package search is an in-process full-text index over the plant catalog.
+//
+// Text is split into lowercase tokens. A query token matches an indexed
+// term exactly, as a prefix of it, or within a small edit distance, with
+// decreasing credit. Every query token must match somewhere in a plant;
+// the plant's score sums the best credit per query token, weighted by the
+// field it matched in.
+package search
+
+import (
+	"cmp"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// Credit for each kind of term match.
+const (
+	exactCredit  = 1.0
+	prefixCredit = 0.8
+	typo1Credit  = 0.6 // one edit away
+	typo2Credit  = 0.4 // two edits away
+)
+
+// minPrefix is the shortest query token that matches term prefixes.
+const minPrefix = 2
+
+// field is one searchable part of a plant.
+type field struct {
+	name   string
+	weight float64
+	text   func(models.Plant) []string
+}
+
+// fields lists what is indexed, most significant first.
+var fields = []field{
+	{"name", 4, func(p models.Plant) []string { return []string{p.Name} }},
+	{"aliases", 4, func(p models.Plant) []string { return p.Aliases }},
+	{"scientificName", 3, func(p models.Plant) []string { return []string{p.ScientificName} }},
+	{"synonyms", 3, func(p models.Plant) []string { return p.Synonyms }},
+	{"taxonomy", 2, func(p models.Plant) []string {
+		t := p.Taxonomy
+		return []string{t.Family, t.Genus, t.Species, t.Cultivar}
+	}},
+	{"features", 1.5, func(p models.Plant) []string { return p.Features }},
+	{"description", 1, func(p models.Plant) []string { return []string{p.Description} }},
+}
+
+type posting struct {
+	doc   int
+	field int
+}
+
+// Index is an immutable search index over a set of plants.
+type Index struct {
+	plants   []models.Plant
+	postings map[string][]posting
+	vocab    []string         // sorted keys of postings
+	names    map[string][]int // normalised full name → docs, for Lookup
+}
+
+// New indexes plants.
+func New(plants []models.Plant) *Index {
+	ix := &Index{plants: plants, postings: make(map[string][]posting), names: make(map[string][]int)}
+	for doc, p := range plants {
+		for _, name := range plantNames(p) {
+			if key := normalize(name); key != "" && !slices.Contains(ix.names[key], doc) {
+				ix.names[key] = append(ix.names[key], doc)
+			}
+		}
+		for fi, f := range fields {
+			for _, s := range f.text(p) {
+				for _, t := range tokenize(s) {
+					ps := ix.postings[t.text]
+					if n := len(ps); n == 0 || ps[n-1] != (posting{doc, fi}) {
+						ix.postings[t.text] = append(ps, posting{doc, fi})
+					}
+				}
+			}
+		}
+	}
+	for term := range ix.postings {
+		ix.vocab = append(ix.vocab, term)
+	}
+	slices.Sort(ix.vocab)
+	return ix
+}
+
+// Hit is one search result. Highlights holds, per matched field, the
+// field text as HTML-escaped markup with matches wrapped in <mark>.
+type Hit struct {
+	Plant      models.Plant      `json:"plant"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Search returns the plants matching every token of q, best first and by
+// name within equal scores. A limit of zero or less means no limit.
+func (ix *Index) Search(q string, limit int) []Hit {
+	var terms []string
+	for _, t := range tokenize(q) {
+		if !slices.Contains(terms, t.text) {
+			terms = append(terms, t.text)
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// total[doc] sums the best weighted credit per query term; matched
+	// counts the query terms the doc matched.
+	total := make([]float64, len(ix.plants))
+	matched := make([]int, len(ix.plants))
+	best := make([]float64, len(ix.plants))
+	for _, q := range terms {
+		clear(best)
+		for _, term := range ix.candidates(q) {
+			credit := matchCredit(q, term)
+			for _, p := range ix.postings[term] {
+				best[p.doc] = max(best[p.doc], credit*fields[p.field].weight)
+			}
+		}
+		for doc, b := range best {
+			if b > 0 {
+				total[doc] += b
+				matched[doc]++
+			}
+		}
+	}
+
+	var hits []Hit
+	for doc, n := range matched {
+		if n == len(terms) {
+			hits = append(hits, Hit{Plant: ix.plants[doc], Score: total[doc], Highlights: highlights(ix.plants[doc], terms)})
+		}
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Plant.Name, b.Plant.Name)
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// plantNames lists every name a plant is known by, including its bare
+// genus, which the other plants of that genus share.
+func plantNames(p models.Plant) []string {
+	names := []string{p.ID, p.Name, p.ScientificName, p.Taxonomy.Genus}
+	names = append(names, p.Aliases...)
+	names = append(names, p.Synonyms...)
+	if t := p.Taxonomy; t.Genus != "" && t.Species != "" {
+		names = append(names, t.Genus+" "+t.Species)
+		if t.Cultivar != "" {
+			names = append(names, t.Genus+" "+t.Species+" "+t.Cultivar)
+		}
+	}
+	return names
+}
+
+// normalize reduces a name to its lowercase tokens joined by spaces, so
+// "Devil's Ivy", "devils-ivy" and "DEVILS IVY" are the same key.
+func normalize(name string) string {
+	var words []string
+	for _, t := range tokenize(name) {
+		words = append(words, t.text)
+	}
+	return strings.Join(words, " ")
+}
+
+// Lookup returns the plants known by name, whether as ID, common name,
+// alias, scientific name, synonym or genus, ignoring case and punctuation.
+// More than one plant means the name is ambiguous.
+func (ix *Index) Lookup(name string) []models.Plant {
+	var out []models.Plant
+	for _, doc := range ix.names[normalize(name)] {
+		out = append(out, ix.plants[doc])
+	}
+	return out
+}
+
+// candidates returns the vocabulary terms q may match: terms it is a
+// prefix of, found by binary search, and terms within typo distance.
+func (ix *Index) candidates(q string) []string {
+	var out []string
+	if utf8.RuneCountInString(q) >= minPrefix {
+		i, _ := slices.BinarySearch(ix.vocab, q)
+		for ; i < len(ix.vocab) && strings.HasPrefix(ix.vocab[i], q); i++ {
+			out = append(out, ix.vocab[i])
+		}
+	} else if _, ok := ix.postings[q]; ok {
+		out = append(out, q)
+	}
+	if maxTypos(q) > 0 {
+		for _, term := range ix.vocab {
+			if !strings.HasPrefix(term, q) && matchCredit(q, term) > 0 {
+				out = append(out, term)
+			}
+		}
+	}
+	return out
+}
+
+// maxTypos is how many edits a query token of this length may contain.
+// Short tokens get none: with three letters nearly everything is one
+// edit away from something.
+func maxTypos(q string) int {
+	switch n := utf8.RuneCountInString(q); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// matchCredit scores how well query token q matches indexed term t.
+func matchCredit(q, t string) float64 {
+	switch {
+	case q == t:
+		return exactCredit
+	case utf8.RuneCountInString(q) >= minPrefix && strings.HasPrefix(t, q):
+		return prefixCredit
+	}
+	limit := maxTypos(q)
+	switch d := editDistance(q, t, limit); {
+	case d > limit:
+		return 0
+	case d == 1:
+		return typo1Credit
+	case d == 2:
+		return typo2Credit
+	}
+	return 0
+}
+
+// editDistance returns the optimal string alignment distance between a
+// and b (insertions, deletions, substitutions and adjacent
+// transpositions), or limit+1 as soon as it must exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return min(prev[len(rb)], limit+1)
+}
+
+// token is a normalised word and where it came from in the source text.
+type token struct {
+	text       string
+	start, end int // byte offsets in the source
+}
+
+// tokenize splits s into lowercase runs of letters and digits.
+// Apostrophes inside words are dropped, so "law's" indexes as "laws".
+func tokenize(s string) []token {
+	var out []token
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			out = append(out, token{b.String(), start, end})
+			b.Reset()
+			start = -1
+		}
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && start >= 0:
+			// skip, stay inside the word
+		default:
+			flush(i)
+		}
+	}
+	flush(len(s))
+	return out
+}
+
+// snippetLen caps highlighted description text, in bytes.
+const snippetLen = 160
+
+// highlights marks the query terms in every field of p they match.
+func highlights(p models.Plant, terms []string) map[string]string {
+	out := make(map[string]string)
+	for _, f := range fields {
+		var parts []string
+		for _, s := range f.text(p) {
+			if h, ok := highlight(s, terms, f.name == "description"); ok {
+				parts = append(parts, h)
+			}
+		}
+		if len(parts) > 0 {
+			out[f.name] = strings.Join(parts, ", ")
+		}
+	}
+	return out
+}
+
+// highlight escapes s and wraps tokens matching any term in <mark>. Long
+// text is cut to a snippet around the first match when snippet is set.
+func highlight(s string, terms []string, snippet bool) (string, bool) {
+	toks := tokenize(s)
+	var marks []token
+	for _, t := range toks {
+		for _, q := range terms {
+			if matchCredit(q, t.text) > 0 {
+				marks = append(marks, t)
+				break
+			}
+		}
+	}
+	if len(marks) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(s)
+	if snippet && len(s) > snippetLen {
+		// Start a few words before the first match, end on a word boundary.
+		i := slices.IndexFunc(toks, func(t token) bool { return t.start == marks[0].start })
+		from = toks[max(i-4, 0)].start
+		to = from
+		for _, t := range toks {
+			if t.start >= from && t.end-from <= snippetLen {
+				to = t.end
+			}
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range marks {
+		if m.start < from || m.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(s[pos:m.start]))
+		b.WriteString("<mark>" + html.EscapeString(s[m.start:m.end]) + "</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(s[pos:to]))
+	if to < len(s) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
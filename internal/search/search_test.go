@@ -0,0 +1,243 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/example/leaf-love-go/internal/data"
+	"github.com/example/leaf-love-go/internal/models"
+)
+
+// catalogIndex indexes the repository's plant catalog.
+func catalogIndex(t *testing.T) *Index {
+	t.Helper()
+	store, err := data.NewStore("../../catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(store.Snapshot().Plants)
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []token
+	}{
+		{"", nil},
+		{"Snake Plant", []token{{"snake", 0, 5}, {"plant", 6, 11}}},
+		{"Mother-in-law's Tongue", []token{{"mother", 0, 6}, {"in", 7, 9}, {"laws", 10, 15}, {"tongue", 16, 22}}},
+		{"devil’s  ivy!", []token{{"devils", 0, 9}, {"ivy", 11, 14}}},
+		{"'quoted 2x", []token{{"quoted", 1, 7}, {"2x", 8, 10}}},
+		{"Ölbaum", []token{{"ölbaum", 0, 7}}},
+	}
+	for _, tc := range tests {
+		if got := tokenize(tc.in); !slices.Equal(got, tc.want) {
+			t.Errorf("tokenize(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"", "", 2, 0},
+		{"snake", "snake", 2, 0},
+		{"snake", "snak", 2, 1},   // deletion
+		{"snake", "snakes", 2, 1}, // insertion
+		{"snake", "snoke", 2, 1},  // substitution
+		{"snake", "snaek", 2, 1},  // adjacent transposition
+		{"lavandar", "lavender", 2, 2},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 2, 3}, // cut off at limit+1
+		{"fern", "fernery", 2, 3},   // length difference alone exceeds the limit
+		{"pothos", "ivy", 1, 2},
+		{"ölbaum", "olbaum", 1, 1}, // runes, not bytes
+	}
+	for _, tc := range tests {
+		if got := editDistance(tc.a, tc.b, tc.limit); got != tc.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tc.a, tc.b, tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestMaxTypos(t *testing.T) {
+	tests := []struct {
+		q    string
+		want int
+	}{
+		{"ivy", 0},
+		{"fern", 1},
+		{"monstra", 1},
+		{"lavandar", 2},
+		{"ölbä", 1}, // counted in runes
+	}
+	for _, tc := range tests {
+		if got := maxTypos(tc.q); got != tc.want {
+			t.Errorf("maxTypos(%q) = %d, want %d", tc.q, got, tc.want)
+		}
+	}
+}
+
+func TestMatchCredit(t *testing.T) {
+	tests := []struct {
+		q, term string
+		want    float64
+	}{
+		{"snake", "snake", exactCredit},
+		{"lav", "lavender", prefixCredit},
+		{"l", "lavender", 0}, // below minPrefix
+		{"snaek", "snake", typo1Credit},
+		{"monstra", "monstera", typo1Credit},
+		{"lavandar", "lavender", typo2Credit},
+		{"lavandr", "lavender", 0}, // two edits, but seven letters allow one
+		{"ivx", "ivy", 0},          // too short for typos
+		{"orchid", "ivy", 0},
+	}
+	for _, tc := range tests {
+		if got := matchCredit(tc.q, tc.term); got != tc.want {
+			t.Errorf("matchCredit(%q, %q) = %v, want %v", tc.q, tc.term, got, tc.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ix := catalogIndex(t)
+	tests := []struct {
+		q     string
+		first string // "" for no hits
+	}{
+		{"snake plant", "snake-plant"},
+		{"mother-in-law's tongue", "snake-plant"},
+		{"Mother in laws tongue", "snake-plant"},
+		{"pohtos", "pothos"},      // one edit: transposition
+		{"lavandar", "lavender"},  // two edits
+		{"sansev", "snake-plant"}, // prefix of the genus
+		{"snake banana", ""},      // every token must match
+		{"  ", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.q, func(t *testing.T) {
+			hits := ix.Search(tc.q, 0)
+			if tc.first == "" {
+				if len(hits) != 0 {
+					t.Errorf("got %d hits, want none", len(hits))
+				}
+				return
+			}
+			if len(hits) == 0 || hits[0].Plant.ID != tc.first {
+				var got []string
+				for _, h := range hits {
+					got = append(got, h.Plant.ID)
+				}
+				t.Errorf("hits = %v, want %s first", got, tc.first)
+			}
+		})
+	}
+
+	if hits := ix.Search("plant", 2); len(hits) != 2 {
+		t.Errorf("limit 2 gave %d hits", len(hits))
+	}
+}
+
+func TestSearchRanksExactAboveTypos(t *testing.T) {
+	ix := New([]models.Plant{
+		{ID: "typo", Name: "Ferm"},
+		{ID: "prefix", Name: "Fernery"},
+		{ID: "exact", Name: "Fern"},
+	})
+	var got []string
+	for _, h := range ix.Search("fern", 0) {
+		got = append(got, h.Plant.ID)
+	}
+	if want := []string{"exact", "prefix", "typo"}; !slices.Equal(got, want) {
+		t.Errorf("hits = %v, want %v", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		terms []string
+		want  string
+	}{
+		{"exact", "Snake Plant", []string{"snake"}, "<mark>Snake</mark> Plant"},
+		{"apostrophe kept in the mark", "Devil's Ivy", []string{"devils"}, "<mark>Devil&#39;s</mark> Ivy"},
+		{"typo only", "Lavender and lavandula", []string{"lavendr"}, "<mark>Lavender</mark> and lavandula"},
+		{"escapes around marks", `<b>Tom</b> & "Jerry"`, []string{"jerry"}, `&lt;b&gt;Tom&lt;/b&gt; &amp; &#34;<mark>Jerry</mark>&#34;`},
+		{"escapes inside marks", "Fern&Moss", []string{"moss"}, "Fern&amp;<mark>Moss</mark>"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := highlight(tc.s, tc.terms, false)
+			if !ok || got != tc.want {
+				t.Errorf("highlight = %q, %v; want %q", got, ok, tc.want)
+			}
+		})
+	}
+	if got, ok := highlight("Snake Plant", []string{"orchid"}, false); ok {
+		t.Errorf("highlight without a match = %q, want none", got)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	s := strings.Repeat("leaf ", 40) + "rare <orchid> " + strings.Repeat("stem ", 40)
+	got, ok := highlight(s, []string{"orchid"}, true)
+	if !ok {
+		t.Fatal("no match")
+	}
+	if want := "…leaf leaf leaf rare &lt;<mark>orchid</mark>&gt; stem"; !strings.HasPrefix(got, want) {
+		t.Errorf("snippet = %q, want it to start %q", got, want)
+	}
+	if !strings.HasSuffix(got, "stem…") {
+		t.Errorf("snippet = %q, want it cut after a word", got)
+	}
+	if plain := strings.NewReplacer("<mark>", "", "</mark>", "", "&lt;", "<", "&gt;", ">", "…", "").Replace(got); len(plain) > snippetLen {
+		t.Errorf("snippet text is %d bytes, want at most %d", len(plain), snippetLen)
+	}
+
+	if got, _ := highlight("Short text about an orchid.", []string{"orchid"}, true); got != "Short text about an <mark>orchid</mark>." {
+		t.Errorf("short text = %q, want it whole", got)
+	}
+}
+
+func TestHitHighlights(t *testing.T) {
+	hits := catalogIndex(t).Search("devils ivy", 1)
+	if len(hits) != 1 || hits[0].Plant.ID != "pothos" {
+		t.Fatalf("hits = %v, want pothos", hits)
+	}
+	if got := hits[0].Highlights["aliases"]; !strings.Contains(got, "<mark>Devil&#39;s</mark> <mark>Ivy</mark>") {
+		t.Errorf("aliases highlight = %q", got)
+	}
+	if _, ok := hits[0].Highlights["description"]; ok {
+		t.Errorf("description highlighted without a match: %v", hits[0].Highlights)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	ix := catalogIndex(t)
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"pothos", []string{"pothos"}},
+		{"DEVILS IVY", []string{"pothos"}},
+		{"Scindapsus aureus", []string{"pothos"}},
+		{"Sansevieria", []string{"snake-plant"}},
+		{"Mother-in-law's Tongue", []string{"snake-plant"}},
+		{"snake", nil}, // names match whole, not by token
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, p := range ix.Lookup(tc.name) {
+			got = append(got, p.ID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("Lookup(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Package web holds the HTML templates and static assets, embedded so the
+// server binary runs from any working directory.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates/*.html static
+var files embed.FS
+
+// Templates returns the embedded page templates, one *.html file per page.
+func Templates() fs.FS { return sub("templates") }
+
+// Static returns the embedded files served under /static/.
+func Static() fs.FS { return sub("static") }
+
+func sub(dir string) fs.FS {
+	f, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err) // dir is a compile-time constant embedded above
+	}
+	return f
+}